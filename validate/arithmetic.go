@@ -0,0 +1,141 @@
+package validate
+
+import (
+	"time"
+
+	"github.com/olivoil/pkg/validate/internal/lang"
+	"github.com/pkg/errors"
+)
+
+// arithmetic evaluates `lhs op rhs` for number, integer, duration and time operands.
+// Integers are promoted to numbers when mixed with them, durations can be scaled by numbers,
+// and times can be shifted by durations or subtracted from each other.
+func arithmetic(op lang.Token, lhs, rhs interface{}) (interface{}, error) {
+	l, err := normalize(lhs)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidParamType, "cannot use %v in arithmetic", lhs)
+	}
+
+	r, err := normalize(rhs)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidParamType, "cannot use %v in arithmetic", rhs)
+	}
+
+	switch a := l.(type) {
+	case int64:
+		switch b := r.(type) {
+		case int64:
+			return integerArithmetic(op, a, b)
+		case float64:
+			return numberArithmetic(op, float64(a), b)
+		case time.Duration:
+			if op == lang.MUL {
+				return time.Duration(a) * b, nil
+			}
+		}
+	case float64:
+		switch b := r.(type) {
+		case int64:
+			return numberArithmetic(op, a, float64(b))
+		case float64:
+			return numberArithmetic(op, a, b)
+		case time.Duration:
+			if op == lang.MUL {
+				return time.Duration(a * float64(b)), nil
+			}
+		}
+	case time.Duration:
+		switch b := r.(type) {
+		case time.Duration:
+			switch op {
+			case lang.ADD:
+				return a + b, nil
+			case lang.SUB:
+				return a - b, nil
+			case lang.DIV:
+				if b == 0 {
+					return nil, errDivisionByZero(lhs, rhs)
+				}
+				return float64(a) / float64(b), nil
+			}
+		case int64:
+			return durationArithmetic(op, a, float64(b), lhs, rhs)
+		case float64:
+			return durationArithmetic(op, a, b, lhs, rhs)
+		}
+	case time.Time:
+		switch b := r.(type) {
+		case time.Duration:
+			switch op {
+			case lang.ADD:
+				return a.Add(b), nil
+			case lang.SUB:
+				return a.Add(-b), nil
+			}
+		case time.Time:
+			if op == lang.SUB {
+				return a.Sub(b), nil
+			}
+		}
+	}
+
+	return nil, errors.Wrapf(ErrInvalidParamType, "cannot evaluate %v %s %v", lhs, op, rhs)
+}
+
+// integerArithmetic evaluates `a op b` for integers.
+func integerArithmetic(op lang.Token, a, b int64) (interface{}, error) {
+	switch op {
+	case lang.ADD:
+		return a + b, nil
+	case lang.SUB:
+		return a - b, nil
+	case lang.MUL:
+		return a * b, nil
+	case lang.DIV:
+		if b == 0 {
+			return nil, errDivisionByZero(a, b)
+		}
+		return a / b, nil
+	}
+
+	return nil, errors.Wrapf(ErrUnknownExpression, "unknown operator %s", op)
+}
+
+// numberArithmetic evaluates `a op b` for numbers.
+func numberArithmetic(op lang.Token, a, b float64) (interface{}, error) {
+	switch op {
+	case lang.ADD:
+		return a + b, nil
+	case lang.SUB:
+		return a - b, nil
+	case lang.MUL:
+		return a * b, nil
+	case lang.DIV:
+		if b == 0 {
+			return nil, errDivisionByZero(a, b)
+		}
+		return a / b, nil
+	}
+
+	return nil, errors.Wrapf(ErrUnknownExpression, "unknown operator %s", op)
+}
+
+// durationArithmetic scales the duration `d` by the number `n`.
+func durationArithmetic(op lang.Token, d time.Duration, n float64, lhs, rhs interface{}) (interface{}, error) {
+	switch op {
+	case lang.MUL:
+		return time.Duration(float64(d) * n), nil
+	case lang.DIV:
+		if n == 0 {
+			return nil, errDivisionByZero(lhs, rhs)
+		}
+		return time.Duration(float64(d) / n), nil
+	}
+
+	return nil, errors.Wrapf(ErrInvalidParamType, "cannot evaluate %v %s %v", lhs, op, rhs)
+}
+
+// errDivisionByZero returns the error for dividing `lhs` by zero.
+func errDivisionByZero(lhs, rhs interface{}) error {
+	return errors.Wrapf(ErrInvalidParamType, "division by zero in %v / %v", lhs, rhs)
+}
@@ -1,7 +1,6 @@
 package validate
 
 import (
-	"fmt"
 	"reflect"
 	"regexp"
 	"time"
@@ -22,6 +21,12 @@ var builtins = Validations{
 	"gt":        ValidationFunc(GreaterThan),
 	"gte":       ValidationFunc(GreaterThanOrEqual),
 	"rfc3339":   SimpleStringValidationFunc(RFC3339),
+	"email":     StringValidationWithStringArgsFunc(Email),
+	"phone":     StringValidationWithStringArgsFunc(Phone),
+
+	"required_if":      ValidationFunc(RequiredIf),
+	"required_with":    ValidationFunc(RequiredWith),
+	"required_without": ValidationFunc(RequiredWithout),
 }
 
 // LessThan
@@ -42,9 +47,11 @@ func LessThan(i interface{}, args ...interface{}) error {
 		}
 
 		return lessThantimeDuration(v, durations...)
+	case time.Time:
+		return compareTimes(v, args, "lt", func(cmp int) bool { return cmp < 0 })
 	}
 
-	return errors.Wrapf(ErrIncompatibleFieldType, "lt expects a numeric field type or a duration, got %v", i)
+	return errors.Wrapf(ErrIncompatibleFieldType, "lt expects a numeric field type, a duration or a time, got %v", i)
 }
 
 func LessThanOrEqual(i interface{}, args ...interface{}) error {
@@ -64,9 +71,11 @@ func LessThanOrEqual(i interface{}, args ...interface{}) error {
 		}
 
 		return lessThanOrEqualTotimeDuration(v, durations...)
+	case time.Time:
+		return compareTimes(v, args, "lte", func(cmp int) bool { return cmp <= 0 })
 	}
 
-	return errors.Wrapf(ErrIncompatibleFieldType, "lte expects a numeric field type or a duration, got %v", i)
+	return errors.Wrapf(ErrIncompatibleFieldType, "lte expects a numeric field type, a duration or a time, got %v", i)
 }
 
 func GreaterThan(i interface{}, args ...interface{}) error {
@@ -86,9 +95,11 @@ func GreaterThan(i interface{}, args ...interface{}) error {
 		}
 
 		return greaterThantimeDuration(v, durations...)
+	case time.Time:
+		return compareTimes(v, args, "gt", func(cmp int) bool { return cmp > 0 })
 	}
 
-	return errors.Wrapf(ErrIncompatibleFieldType, "lte expects a numeric field type or a duration, got %v", i)
+	return errors.Wrapf(ErrIncompatibleFieldType, "gt expects a numeric field type, a duration or a time, got %v", i)
 }
 
 func GreaterThanOrEqual(i interface{}, args ...interface{}) error {
@@ -108,9 +119,36 @@ func GreaterThanOrEqual(i interface{}, args ...interface{}) error {
 		}
 
 		return greaterThanOrEqualTotimeDuration(v, durations...)
+	case time.Time:
+		return compareTimes(v, args, "gte", func(cmp int) bool { return cmp >= 0 })
+	}
+
+	return errors.Wrapf(ErrIncompatibleFieldType, "gte expects a numeric field type, a duration or a time, got %v", i)
+}
+
+// compareTimes validates `i` against every time in `args` for the comparison `op`. `valid` receives -1, 0 or +1
+// when `i` is before, equal to or after the other time.
+func compareTimes(i time.Time, args []interface{}, op string, valid func(cmp int) bool) error {
+	times, err := toTimes(args)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range times {
+		cmp := 0
+		switch {
+		case i.Before(other):
+			cmp = -1
+		case i.After(other):
+			cmp = 1
+		}
+
+		if !valid(cmp) {
+			return &ComparisonError{Value: i, Op: op, Bound: other}
+		}
 	}
 
-	return errors.Wrapf(ErrIncompatibleFieldType, "lte expects a numeric field type or a duration, got %v", i)
+	return nil
 }
 
 // Blacklist validates `i` is not one of `args`.
@@ -122,7 +160,7 @@ func Blacklist(i interface{}, args ...interface{}) error {
 
 	for _, arg := range args {
 		if val == arg {
-			return fmt.Errorf("%v in (%v)", i, args)
+			return &InclusionError{Value: i, Values: copyArgs(args), Excluded: true}
 		}
 	}
 
@@ -141,7 +179,7 @@ func Whitelist(i interface{}, args ...interface{}) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("%v not in (%v)", i, args)
+	return &InclusionError{Value: i, Values: copyArgs(args)}
 }
 
 // Lengther enables `Len` to be used on custom types.
@@ -164,7 +202,7 @@ func Len(i interface{}, args ...int64) error {
 		actual := v.Len()
 		expected := int(args[0])
 		if actual != expected {
-			return fmt.Errorf("expected %v to have length %d, got %d", i, expected, actual)
+			return &LengthError{Value: i, Expected: expected, Actual: actual}
 		}
 		return nil
 	}
@@ -173,7 +211,7 @@ func Len(i interface{}, args ...int64) error {
 		actual := l.Len()
 		expected := int(args[0])
 		if actual != expected {
-			return fmt.Errorf("expected %v to have length %d, got %d", i, expected, actual)
+			return &LengthError{Value: i, Expected: expected, Actual: actual}
 		}
 		return nil
 	}
@@ -198,7 +236,7 @@ func Match(i interface{}, args ...interface{}) error {
 	}
 
 	if !r.MatchString(s) {
-		return fmt.Errorf("%v does not match %s", i, r.String())
+		return &MatchError{Value: s, Pattern: r.String()}
 	}
 
 	return nil
@@ -220,15 +258,54 @@ func Required(i interface{}) error {
 		return nil
 	}
 
-	return fmt.Errorf("expected %v not to be nil", i)
+	return &NilError{Value: i}
+}
+
+// RequiredIf validates `i` is not nil when `args[0]` equals one of `args[1:]` (i.e. `required_if($.Method, 'card')`).
+func RequiredIf(i interface{}, args ...interface{}) error {
+	if len(args) < 2 {
+		return errors.Wrap(ErrInvalidParamType, "required_if expects a value and at least one value to compare it to")
+	}
+
+	for _, arg := range args[1:] {
+		if equal(args[0], arg) {
+			return Required(i)
+		}
+	}
+
+	return nil
+}
+
+// RequiredWith validates `i` is not nil when any of `args` is not nil (i.e. `required_with($.Street)`).
+func RequiredWith(i interface{}, args ...interface{}) error {
+	for _, arg := range args {
+		if !isZero(arg) {
+			return Required(i)
+		}
+	}
+
+	return nil
+}
+
+// RequiredWithout validates `i` is not nil when any of `args` is nil (i.e. `required_without($.Email)`).
+func RequiredWithout(i interface{}, args ...interface{}) error {
+	for _, arg := range args {
+		if isZero(arg) {
+			return Required(i)
+		}
+	}
+
+	return nil
 }
 
 // Nil indicates if `i` is a nil value.
 func Nil(i interface{}) error {
 	v := reflect.ValueOf(i)
-	err := fmt.Errorf("expected %v to be nil", i)
+	err := &NilError{Value: i, Nil: true}
 
 	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
 	case reflect.String, reflect.Array:
 		if v.Len() != 0 {
 			return err
@@ -297,6 +374,9 @@ func Nil(i interface{}) error {
 
 // RFC3339 validates `i` is formatted with time.RFC3339.
 func RFC3339(i string) error {
-	_, err := time.Parse(time.RFC3339, i)
-	return err
+	if _, err := time.Parse(time.RFC3339, i); err != nil {
+		return &FormatError{Value: i, Format: "rfc3339", Reason: err.Error()}
+	}
+
+	return nil
 }
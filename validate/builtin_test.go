@@ -102,6 +102,24 @@ func TestBuiltin_Required(t *testing.T) {
 				IsValid: true,
 			},
 		},
+		`interface`: []testCase{
+			{
+				Title: "nil",
+				V: struct {
+					Any interface{} `validate:"required"`
+				}{},
+				IsValid: false,
+			},
+			{
+				Title: "not nil",
+				V: struct {
+					Any interface{} `validate:"required"`
+				}{
+					Any: "ok",
+				},
+				IsValid: true,
+			},
+		},
 	}
 
 	tests.Test(t, validate.New())
@@ -325,25 +343,27 @@ func TestBuiltin_LessThan(t *testing.T) {
 				IsValid: true,
 			},
 			{
-				Title: "allows formatted date",
+				Title: "allows smaller values",
 				V: V{
-					Timestamp: time.Now().Format(time.RFC3339),
+					Float64: 4.9,
+					Float32: 4.9,
+					Int64:   4,
 				},
 				IsValid: true,
 			},
 		},
 		`invalid`: []testCase{
 			{
-				Title: "denies other date formats",
+				Title: "denies equal values",
 				V: V{
-					Timestamp: time.Now().Format(time.Kitchen),
+					Int64: 5,
 				},
 				IsValid: false,
 			},
 			{
-				Title: "denies other date formats",
+				Title: "denies greater values",
 				V: V{
-					Timestamp: time.Now().Format(time.RFC822Z),
+					Float64: 5.1,
 				},
 				IsValid: false,
 			},
@@ -393,3 +413,186 @@ func TestBuiltin_RFC3339(t *testing.T) {
 
 	tests.Test(t, validate.New())
 }
+
+func TestBuiltin_Email(t *testing.T) {
+	type V struct {
+		Email string `validate:"email"`
+	}
+
+	valid := []string{
+		"user@example.com",
+		"first.last+tag@sub.example.co.uk",
+		"user@localhost",
+		"!#$%&'*+-/=?^_`{|}~@example.com",
+		`"john doe"@example.com`,
+		`"a\"b"@example.com`,
+		"user@[127.0.0.1]",
+		"user@[IPv6:2001:db8::1]",
+		"user@xn--bcher-kva.example",
+	}
+	invalid := []string{
+		"",
+		"user",
+		"@example.com",
+		"user@",
+		".user@example.com",
+		"user.@example.com",
+		"us..er@example.com",
+		"us er@example.com",
+		`"a"b"@example.com`,
+		"user@-example.com",
+		"user@example..com",
+		"user@exa_mple.com",
+		"user@[300.0.0.1]",
+		"user@bücher.example",
+	}
+
+	tests := testCases{`valid`: []testCase{}, `invalid`: []testCase{}}
+	for _, email := range valid {
+		tests[`valid`] = append(tests[`valid`], testCase{Title: email, V: V{Email: email}, IsValid: true})
+	}
+	for _, email := range invalid {
+		tests[`invalid`] = append(tests[`invalid`], testCase{Title: email, V: V{Email: email}, IsValid: false})
+	}
+
+	tests.Test(t, validate.New())
+}
+
+func TestBuiltin_Email_Options(t *testing.T) {
+	assert.Error(t, validate.Value("user@localhost", "email('tld')"))
+	assert.Error(t, validate.Value("user@[127.0.0.1]", "email('tld')"))
+	assert.Error(t, validate.Value("user@example.123", "email('tld')"))
+	assert.NoError(t, validate.Value("user@example.com", "email('tld')"))
+
+	assert.Error(t, validate.Value(`"john doe"@example.com`, "email('unquoted')"))
+	assert.NoError(t, validate.Value("john.doe@example.com", "email('unquoted')"))
+
+	assert.NoError(t, validate.Value("user@bücher.example", "email('idn')"))
+	assert.NoError(t, validate.Value("user@例え.jp", "email('idn','tld')"))
+	assert.Error(t, validate.Value("user@bü_cher.example", "email('idn')"))
+
+	assert.Error(t, validate.Value("user@example.com", "email('unknown')"))
+}
+
+func TestBuiltin_Phone(t *testing.T) {
+	type V struct {
+		Phone   string `validate:"phone"`
+		Mobile  string `validate:"omitempty,phone('FR')"`
+		Contact string `validate:"omitempty,email|phone"`
+	}
+
+	tests := testCases{
+		`valid`: []testCase{
+			{
+				Title:   "E.164",
+				V:       V{Phone: "+14155552671"},
+				IsValid: true,
+			},
+			{
+				Title:   "with separators",
+				V:       V{Phone: "+1 (415) 555-2671"},
+				IsValid: true,
+			},
+			{
+				Title:   "national number of region",
+				V:       V{Phone: "+442071838750", Mobile: "06 12 34 56 78"},
+				IsValid: true,
+			},
+			{
+				Title:   "email or phone",
+				V:       V{Phone: "+442071838750", Contact: "user@example.com"},
+				IsValid: true,
+			},
+		},
+		`invalid`: []testCase{
+			{
+				Title:   "empty",
+				V:       V{},
+				IsValid: false,
+			},
+			{
+				Title:   "missing country code",
+				V:       V{Phone: "4155552671"},
+				IsValid: false,
+			},
+			{
+				Title:   "too long",
+				V:       V{Phone: "+1415555267112345"},
+				IsValid: false,
+			},
+			{
+				Title:   "too short",
+				V:       V{Phone: "+1415"},
+				IsValid: false,
+			},
+			{
+				Title:   "invalid country code",
+				V:       V{Phone: "+04155552671"},
+				IsValid: false,
+			},
+			{
+				Title:   "letters",
+				V:       V{Phone: "+1415CALLNOW"},
+				IsValid: false,
+			},
+			{
+				Title:   "neither email nor phone",
+				V:       V{Phone: "+14155552671", Contact: "123.555.789"},
+				IsValid: false,
+			},
+		},
+	}
+
+	tests.Test(t, validate.New())
+
+	assert.NoError(t, validate.Value("(415) 555-2671", "phone('us')"))
+	assert.NoError(t, validate.Value("1 415 555 2671", "phone('US')"))
+	assert.Error(t, validate.Value("415 555 2671", "phone('XX')"))
+}
+
+func TestBuiltin_RequiredIf(t *testing.T) {
+	type V struct {
+		Method     string
+		Street     string
+		Email      string
+		CardNumber string `validate:"required_if($.Method, 'card', 'debit')"`
+		City       string `validate:"required_with($.Street)"`
+		Phone      string `validate:"required_without($.Email)"`
+	}
+
+	tests := testCases{
+		`valid`: []testCase{
+			{
+				Title:   "conditions do not apply",
+				V:       V{Method: "cash", Email: "user@example.com"},
+				IsValid: true,
+			},
+			{
+				Title:   "conditions apply and fields are set",
+				V:       V{Method: "debit", CardNumber: "4242", Street: "Main St", City: "Springfield", Phone: "+14155552671"},
+				IsValid: true,
+			},
+		},
+		`invalid`: []testCase{
+			{
+				Title:   "required_if",
+				V:       V{Method: "card", Email: "user@example.com"},
+				IsValid: false,
+			},
+			{
+				Title:   "required_with",
+				V:       V{Street: "Main St", Email: "user@example.com"},
+				IsValid: false,
+			},
+			{
+				Title:   "required_without",
+				V:       V{},
+				IsValid: false,
+			},
+		},
+	}
+
+	tests.Test(t, validate.New())
+
+	assert.Error(t, validate.Value("", "required_if('a')"))
+}
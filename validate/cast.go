@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strconv"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 // normalize between go values and validate-lang value
@@ -70,6 +72,10 @@ func toIntegers(s []interface{}) (integers []int64, err error) {
 
 // toDuration converts `i` to a duration.
 func toDuration(i interface{}) (time.Duration, error) {
+	if d, ok := i.(time.Duration); ok {
+		return d, nil
+	}
+
 	d, err := toInteger(i)
 	return time.Duration(d), err
 }
@@ -86,3 +92,28 @@ func toDurations(s []interface{}) (durations []time.Duration, err error) {
 
 	return
 }
+
+// toTime converts `i` to a time, parsing strings as RFC 3339.
+func toTime(i interface{}) (time.Time, error) {
+	switch t := i.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		return time.Parse(time.RFC3339, t)
+	}
+
+	return time.Time{}, errors.Wrapf(ErrInvalidParamType, "expected a time, got %v", i)
+}
+
+// toTimes converts `s` to a []time.Time.
+func toTimes(s []interface{}) (times []time.Time, err error) {
+	for _, i := range s {
+		t, e := toTime(i)
+		if e != nil {
+			err = e
+		}
+		times = append(times, t)
+	}
+
+	return
+}
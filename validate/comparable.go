@@ -4,16 +4,13 @@
 
 package validate
 
-import (
-	"fmt"
-	"time"
-)
+import "time"
 
 // lessThanfloat64 validates `i` is less than `others`.
 func lessThanfloat64(i float64, others ...float64) error {
 	for _, other := range others {
 		if i >= other {
-			return fmt.Errorf("expected %v to be less than %v", i, other)
+			return &ComparisonError{Value: i, Op: "lt", Bound: other}
 		}
 	}
 
@@ -24,7 +21,7 @@ func lessThanfloat64(i float64, others ...float64) error {
 func lessThanOrEqualTofloat64(i float64, others ...float64) error {
 	for _, other := range others {
 		if i > other {
-			return fmt.Errorf("expected %v to be less than or equal to %v", i, other)
+			return &ComparisonError{Value: i, Op: "lte", Bound: other}
 		}
 	}
 
@@ -35,7 +32,7 @@ func lessThanOrEqualTofloat64(i float64, others ...float64) error {
 func greaterThanfloat64(i float64, others ...float64) error {
 	for _, other := range others {
 		if i <= other {
-			return fmt.Errorf("expected %v to be greater than %v", i, other)
+			return &ComparisonError{Value: i, Op: "gt", Bound: other}
 		}
 	}
 
@@ -46,7 +43,7 @@ func greaterThanfloat64(i float64, others ...float64) error {
 func greaterThanOrEqualTofloat64(i float64, others ...float64) error {
 	for _, other := range others {
 		if i < other {
-			return fmt.Errorf("expected %v to be greater than or equal to %v", i, other)
+			return &ComparisonError{Value: i, Op: "gte", Bound: other}
 		}
 	}
 
@@ -57,7 +54,7 @@ func greaterThanOrEqualTofloat64(i float64, others ...float64) error {
 func lessThanint64(i int64, others ...int64) error {
 	for _, other := range others {
 		if i >= other {
-			return fmt.Errorf("expected %v to be less than %v", i, other)
+			return &ComparisonError{Value: i, Op: "lt", Bound: other}
 		}
 	}
 
@@ -68,7 +65,7 @@ func lessThanint64(i int64, others ...int64) error {
 func lessThanOrEqualToint64(i int64, others ...int64) error {
 	for _, other := range others {
 		if i > other {
-			return fmt.Errorf("expected %v to be less than or equal to %v", i, other)
+			return &ComparisonError{Value: i, Op: "lte", Bound: other}
 		}
 	}
 
@@ -79,7 +76,7 @@ func lessThanOrEqualToint64(i int64, others ...int64) error {
 func greaterThanint64(i int64, others ...int64) error {
 	for _, other := range others {
 		if i <= other {
-			return fmt.Errorf("expected %v to be greater than %v", i, other)
+			return &ComparisonError{Value: i, Op: "gt", Bound: other}
 		}
 	}
 
@@ -90,7 +87,7 @@ func greaterThanint64(i int64, others ...int64) error {
 func greaterThanOrEqualToint64(i int64, others ...int64) error {
 	for _, other := range others {
 		if i < other {
-			return fmt.Errorf("expected %v to be greater than or equal to %v", i, other)
+			return &ComparisonError{Value: i, Op: "gte", Bound: other}
 		}
 	}
 
@@ -101,7 +98,7 @@ func greaterThanOrEqualToint64(i int64, others ...int64) error {
 func lessThantimeDuration(i time.Duration, others ...time.Duration) error {
 	for _, other := range others {
 		if i >= other {
-			return fmt.Errorf("expected %v to be less than %v", i, other)
+			return &ComparisonError{Value: i, Op: "lt", Bound: other}
 		}
 	}
 
@@ -112,7 +109,7 @@ func lessThantimeDuration(i time.Duration, others ...time.Duration) error {
 func lessThanOrEqualTotimeDuration(i time.Duration, others ...time.Duration) error {
 	for _, other := range others {
 		if i > other {
-			return fmt.Errorf("expected %v to be less than or equal to %v", i, other)
+			return &ComparisonError{Value: i, Op: "lte", Bound: other}
 		}
 	}
 
@@ -123,7 +120,7 @@ func lessThanOrEqualTotimeDuration(i time.Duration, others ...time.Duration) err
 func greaterThantimeDuration(i time.Duration, others ...time.Duration) error {
 	for _, other := range others {
 		if i <= other {
-			return fmt.Errorf("expected %v to be greater than %v", i, other)
+			return &ComparisonError{Value: i, Op: "gt", Bound: other}
 		}
 	}
 
@@ -134,7 +131,7 @@ func greaterThantimeDuration(i time.Duration, others ...time.Duration) error {
 func greaterThanOrEqualTotimeDuration(i time.Duration, others ...time.Duration) error {
 	for _, other := range others {
 		if i < other {
-			return fmt.Errorf("expected %v to be greater than or equal to %v", i, other)
+			return &ComparisonError{Value: i, Op: "gte", Bound: other}
 		}
 	}
 
@@ -0,0 +1,653 @@
+package validate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/olivoil/pkg/validate/internal/lang"
+	"github.com/pkg/errors"
+)
+
+// Program is a compiled validation rule.
+// Programs are safe for concurrent use.
+type Program struct {
+	expr lang.Expr
+	// rule is the source of the rule, reported in errors.
+	rule  string
+	check checkFunc
+}
+
+// scope holds the state of a single evaluation of a program.
+type scope struct {
+	ctx context.Context
+	// path of the value being validated.
+	path Path
+	// structs is the bounded context: the structs enclosing the value being validated,
+	// from the root of the document to the struct being validated.
+	structs []reflect.Value
+	// rule is the source of the rule being evaluated.
+	rule string
+	// absent is set for documents, which may omit fields: bound params whose path does not resolve are nil,
+	// and missing values are validated as absent.
+	absent bool
+}
+
+// error returns a validation error for the value being validated.
+func (sc *scope) error(validation string, err error) Error {
+	return Error{Field: sc.path.Leaf(), Path: sc.path, Validation: validation, Err: err, Rule: sc.rule}
+}
+
+// index returns the scope of the item at index `i` of the value being validated.
+func (sc *scope) index(i int) *scope {
+	inner := *sc
+	inner.path = sc.path.Index(i)
+	return &inner
+}
+
+// key returns the scope of the item at `key` of the value being validated.
+func (sc *scope) key(key interface{}) *scope {
+	inner := *sc
+	inner.path = sc.path.Key(key)
+	return &inner
+}
+
+// resolve returns the value of a bound param, following its parsed path.
+func (sc *scope) resolve(param *lang.BoundParam, segments []lang.Segment) (interface{}, error) {
+	i := len(sc.structs) - 1
+	switch param.Ref {
+	case lang.RootRef:
+		i = 0
+	case lang.ParentRef:
+		i -= param.Depth
+	}
+
+	if i < 0 {
+		return nil, &PathError{Param: param.String(), Reason: fmt.Sprintf("no struct %d levels above", param.Depth)}
+	}
+
+	val, err := getValueFromPath(param, segments, sc.structs[i])
+	if _, ok := err.(*PathError); ok && sc.absent {
+		return nil, nil
+	}
+
+	return val, err
+}
+
+// unresolved returns the error of the validation `code` when a bound param does not resolve.
+// Paths that do not resolve because of the data, i.e. an index out of range, fail the validation,
+// other errors are returned as is.
+func (sc *scope) unresolved(validation, code string, val reflect.Value, err error) error {
+	if e, ok := err.(*PathError); !ok || !e.data {
+		return err
+	}
+
+	e := sc.error(validation, err)
+	e.Code, e.Value = code, valueInterface(val)
+	return e
+}
+
+// missing reports whether `val` is a missing field of a document.
+func (sc *scope) missing(val reflect.Value) bool {
+	return sc.absent && !val.IsValid()
+}
+
+// checkFunc validates a value within a scope.
+type checkFunc func(sc *scope, val reflect.Value) error
+
+// argFunc resolves the value of a validation argument within a scope.
+type argFunc func(sc *scope) (interface{}, error)
+
+// Compile parses and compiles a rule with the default validations.
+func Compile(rule string) (*Program, error) {
+	return defaultValidator.Compile(rule)
+}
+
+// Compile parses and compiles a rule into a Program.
+// Validation functions are looked up and literal arguments are converted once, at compile time.
+func (v *Validator) Compile(rule string) (*Program, error) {
+	expr, err := lang.Parse(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.compile(expr, rule)
+}
+
+// compileGroups parses and compiles a rule made of sections for validation groups,
+// i.e. `max(15);create:required,max(15)`. Sections without a group name apply to the DefaultGroup.
+func (v *Validator) compileGroups(rule string) (map[string]*Program, error) {
+	groups, err := lang.ParseGroups(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	programs := make(map[string]*Program, len(groups))
+	for _, group := range groups {
+		program, err := v.compile(group.Expr, group.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		name := group.Name
+		if name == "" {
+			name = DefaultGroup
+		}
+		programs[name] = program
+	}
+
+	return programs, nil
+}
+
+// String returns the string representation of the compiled rule.
+func (p *Program) String() string {
+	return p.expr.String()
+}
+
+// Check validates a single value `i` against the compiled rule.
+func (p *Program) Check(i interface{}) error {
+	return p.CheckContext(context.Background(), i)
+}
+
+// CheckContext validates a single value `i` against the compiled rule.
+// The context is passed to context validations, and evaluation stops when it is done.
+func (p *Program) CheckContext(ctx context.Context, i interface{}) error {
+	return p.run(&scope{ctx: ctx, structs: []reflect.Value{reflect.ValueOf(struct{}{})}, rule: p.rule}, reflect.ValueOf(i))
+}
+
+// run evaluates the program, always reporting validation failures as Errors.
+func (p *Program) run(sc *scope, val reflect.Value) error {
+	errs, err := appendError(nil, p.check(sc, val))
+	if err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// compile turns an expression, parsed from the source `rule`, into a Program.
+func (v *Validator) compile(expr lang.Expr, rule string) (*Program, error) {
+	check, err := v.compileRule(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Program{expr: expr, rule: rule, check: check}, nil
+}
+
+// compileRule compiles the top level of a rule, where modifiers such as `omitempty` are allowed.
+func (v *Validator) compileRule(expr lang.Expr) (checkFunc, error) {
+	rest, omitempty := stripOmitEmpty(expr)
+
+	check := func(sc *scope, val reflect.Value) error { return nil }
+	if rest != nil {
+		var err error
+		if check, err = v.compileExpr(rest); err != nil {
+			return nil, err
+		}
+	}
+
+	if !omitempty {
+		return check, nil
+	}
+
+	tracer, rule := v.tracer, expr.String()
+	return func(sc *scope, val reflect.Value) error {
+		if isEmpty(val) {
+			if tracer != nil {
+				tracer.Trace(Event{Kind: ShortCircuit, Path: sc.path, Rule: rule, Value: valueInterface(val)})
+			}
+			return nil
+		}
+
+		return check(sc, val)
+	}, nil
+}
+
+// stripOmitEmpty removes `omitempty` modifiers from the top-level conjunction of `expr`,
+// and reports whether any was found.
+func stripOmitEmpty(expr lang.Expr) (lang.Expr, bool) {
+	switch e := expr.(type) {
+	case *lang.OmitEmpty:
+		return nil, true
+	case *lang.BinaryExpr:
+		if e.Op != lang.AND {
+			return expr, false
+		}
+
+		lhs, l := stripOmitEmpty(e.LHS)
+		rhs, r := stripOmitEmpty(e.RHS)
+		switch {
+		case !l && !r:
+			return expr, false
+		case lhs == nil:
+			return rhs, true
+		case rhs == nil:
+			return lhs, true
+		}
+
+		return &lang.BinaryExpr{Op: lang.AND, LHS: lhs, RHS: rhs}, true
+	}
+
+	return expr, false
+}
+
+// compileExpr turns an expression into a tree of check functions.
+func (v *Validator) compileExpr(expr lang.Expr) (checkFunc, error) {
+	check, err := v.compileNode(expr)
+	if err != nil || v.tracer == nil {
+		return check, err
+	}
+
+	tracer, rule := v.tracer, expr.String()
+	return func(sc *scope, val reflect.Value) error {
+		tracer.Trace(Event{Kind: RuleEntered, Path: sc.path, Rule: rule, Value: valueInterface(val)})
+		return check(sc, val)
+	}, nil
+}
+
+// compileNode turns a single expression node into a check function.
+func (v *Validator) compileNode(expr lang.Expr) (checkFunc, error) {
+	switch exp := expr.(type) {
+	case *lang.BinaryExpr:
+		return v.compileBinaryExpr(exp)
+	case *lang.ParenExpr:
+		return v.compileExpr(exp.Expr)
+	case *lang.NegativeExpr:
+		return v.compileNegativeExpr(exp)
+	case *lang.EachExpr:
+		return v.compileEachExpr(exp)
+	case *lang.KeysExpr:
+		return v.compileKeysExpr(exp)
+	case *lang.ValuesExpr:
+		return v.compileValuesExpr(exp)
+	case *lang.WhenExpr:
+		return v.compileWhenExpr(exp)
+	case *lang.ComparisonExpr:
+		return v.compileComparisonExpr(exp)
+	case *lang.MessageExpr:
+		return v.compileMessageExpr(exp)
+	case *lang.Call:
+		return v.compileCall(exp)
+	case *lang.OmitEmpty:
+		return nil, errors.Wrap(ErrMisplacedModifier, "omitempty must apply to the whole rule")
+	}
+
+	return nil, errors.Wrap(ErrUnknownExpression, expr.String())
+}
+
+func (v *Validator) compileBinaryExpr(exp *lang.BinaryExpr) (checkFunc, error) {
+	// arithmetic is only meaningful in validation arguments.
+	if exp.Op != lang.AND && exp.Op != lang.OR {
+		return nil, errors.Wrap(ErrUnknownExpression, exp.String())
+	}
+
+	lhs, err := v.compileExpr(exp.LHS)
+	if err != nil {
+		return nil, err
+	}
+
+	rhs, err := v.compileExpr(exp.RHS)
+	if err != nil {
+		return nil, err
+	}
+
+	op, tracer, rule := exp.Op, v.tracer, exp.String()
+	return func(sc *scope, val reflect.Value) error {
+		err := lhs(sc, val)
+		if (err != nil && op == lang.AND) || (err == nil && op == lang.OR) {
+			if tracer != nil {
+				tracer.Trace(Event{Kind: ShortCircuit, Path: sc.path, Rule: rule, Value: valueInterface(val), Err: err})
+			}
+			return err
+		}
+
+		return rhs(sc, val)
+	}, nil
+}
+
+func (v *Validator) compileNegativeExpr(exp *lang.NegativeExpr) (checkFunc, error) {
+	check, err := v.compileExpr(exp.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	validation := exp.String()
+	return func(sc *scope, val reflect.Value) error {
+		switch err := check(sc, val); err.(type) {
+		case nil:
+			e := sc.error(validation, nil)
+			e.Code, e.Value = "not", valueInterface(val)
+			return e
+		case Error, Errors:
+			return nil
+		default:
+			// errors that are not validation errors, i.e. from the context, are returned as is.
+			return err
+		}
+	}, nil
+}
+
+// compileMessageExpr compiles an expression whose validation errors carry the message attached to it.
+// Messages attached to inner expressions take precedence.
+func (v *Validator) compileMessageExpr(exp *lang.MessageExpr) (checkFunc, error) {
+	check, err := v.compileExpr(exp.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	message := exp.Message
+	return func(sc *scope, val reflect.Value) error {
+		err := check(sc, val)
+		switch e := err.(type) {
+		case Error:
+			if e.Message == "" {
+				e.Message = message
+			}
+			return e
+		case Errors:
+			errs := make(Errors, len(e))
+			for i := range e {
+				errs[i] = e[i]
+				if errs[i].Message == "" {
+					errs[i].Message = message
+				}
+			}
+			return errs
+		}
+
+		return err
+	}, nil
+}
+
+func (v *Validator) compileEachExpr(exp *lang.EachExpr) (checkFunc, error) {
+	items, err := v.compileItems(exp.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(sc *scope, val reflect.Value) error {
+		if sc.missing(val) {
+			return nil
+		}
+
+		val = indirectInterface(val)
+		switch val.Kind() {
+		case reflect.Slice, reflect.Array, reflect.String:
+			return items.check(val.Len(), func(i int) (*scope, reflect.Value) {
+				return sc.index(i), val.Index(i)
+			})
+		default:
+			return errors.Wrap(ErrIncompatibleFieldType, "each() requires the value to be an array, slice, or string")
+		}
+	}, nil
+}
+
+func (v *Validator) compileKeysExpr(exp *lang.KeysExpr) (checkFunc, error) {
+	items, err := v.compileItems(exp.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(sc *scope, val reflect.Value) error {
+		if sc.missing(val) {
+			return nil
+		}
+
+		val = indirectInterface(val)
+		if val.Kind() != reflect.Map {
+			return errors.Wrap(ErrIncompatibleFieldType, "keys() requires the value to be a map")
+		}
+
+		keys := sortedMapKeys(val)
+		return items.check(len(keys), func(i int) (*scope, reflect.Value) {
+			return sc.key(keys[i].Interface()), keys[i]
+		})
+	}, nil
+}
+
+func (v *Validator) compileValuesExpr(exp *lang.ValuesExpr) (checkFunc, error) {
+	items, err := v.compileItems(exp.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(sc *scope, val reflect.Value) error {
+		if sc.missing(val) {
+			return nil
+		}
+
+		val = indirectInterface(val)
+		if val.Kind() != reflect.Map {
+			return errors.Wrap(ErrIncompatibleFieldType, "values() requires the value to be a map")
+		}
+
+		keys := sortedMapKeys(val)
+		return items.check(len(keys), func(i int) (*scope, reflect.Value) {
+			return sc.key(keys[i].Interface()), val.MapIndex(keys[i])
+		})
+	}, nil
+}
+
+// itemsCheck validates every item of a collection.
+type itemsCheck struct {
+	item checkFunc
+	// collect causes every failing item to be reported, up to limit errors (0 for no limit).
+	collect bool
+	limit   int
+}
+
+// compileItems compiles the rule applied to every item of a collection.
+func (v *Validator) compileItems(expr lang.Expr) (*itemsCheck, error) {
+	check, err := v.compileExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &itemsCheck{item: check, collect: v.collectEachErrors, limit: v.eachErrorsLimit}, nil
+}
+
+// check validates `n` items, stopping at the first failure unless errors are collected.
+func (c *itemsCheck) check(n int, item func(i int) (*scope, reflect.Value)) error {
+	var errs Errors
+	for i := 0; i < n; i++ {
+		err := c.item(item(i))
+		if err == nil {
+			continue
+		}
+		if !c.collect {
+			return err
+		}
+
+		if errs, err = appendError(errs, err); err != nil {
+			return err
+		}
+		if c.limit > 0 && len(errs) >= c.limit {
+			return errs[:c.limit]
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (v *Validator) compileCall(exp *lang.Call) (checkFunc, error) {
+	// look up validation function
+	f, ok := v.validations.Lookup(exp.Name)
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownValidationFunction, "unknown validation: %s", exp.Name)
+	}
+	cf, withContext := f.(ContextValidation)
+
+	// evaluate constant arguments once, and keep resolvers for the others.
+	params := make([]interface{}, len(exp.Args))
+	bound := make([]argFunc, len(exp.Args))
+	dynamic := false
+	for i, arg := range exp.Args {
+		param, resolve, err := v.compileArg(arg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "in %s", exp.String())
+		}
+
+		params[i], bound[i] = param, resolve
+		dynamic = dynamic || resolve != nil
+	}
+
+	validation, tracer := exp.String(), v.tracer
+	return func(sc *scope, val reflect.Value) error {
+		args := params
+		if dynamic {
+			args = make([]interface{}, len(params))
+			copy(args, params)
+			for i, resolve := range bound {
+				if resolve == nil {
+					continue
+				}
+
+				p, err := resolve(sc)
+				if err != nil {
+					return sc.unresolved(validation, exp.Name, val, err)
+				}
+				args[i] = p
+			}
+		}
+
+		// stop when the context is done.
+		if err := sc.ctx.Err(); err != nil {
+			return err
+		}
+
+		// call validation
+		var err error
+		i := valueInterface(val)
+		if sc.missing(val) {
+			err = validateAbsent(exp.Name, args)
+		} else if withContext {
+			err = cf.ValidateContext(sc.ctx, i, args...)
+		} else {
+			err = f.Validate(i, args...)
+		}
+		if tracer != nil {
+			tracer.Trace(Event{Kind: CallResult, Path: sc.path, Rule: validation, Value: i, Args: copyArgs(args), Err: err})
+		}
+		if err != nil {
+			if ctxErr := sc.ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if errors.Cause(err) == ErrValidationUnavailable {
+				return err
+			}
+
+			e := sc.error(validation, err)
+			e.Code, e.Args, e.Value = exp.Name, copyArgs(args), i
+			return e
+		}
+
+		return nil
+	}, nil
+}
+
+// copyArgs copies the arguments of a call, so errors and events do not share the constant arguments of programs.
+func copyArgs(args []interface{}) []interface{} {
+	return append([]interface{}(nil), args...)
+}
+
+// compileArg compiles a validation argument.
+// Constant arguments are evaluated at compile time and returned with a nil argFunc.
+func (v *Validator) compileArg(arg lang.Expr) (interface{}, argFunc, error) {
+	switch a := arg.(type) {
+	case *lang.BoundParam:
+		segments, err := lang.ParsePath(a.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return nil, func(sc *scope) (interface{}, error) {
+			return sc.resolve(a, segments)
+		}, nil
+	case lang.Literal:
+		return a.Interface(), nil, nil
+	case *lang.ParenExpr:
+		return v.compileArg(a.Expr)
+	case *lang.BinaryExpr:
+		switch a.Op {
+		case lang.ADD, lang.SUB, lang.MUL, lang.DIV:
+			return v.compileOperation(a, arithmetic)
+		case lang.EQ, lang.NEQ, lang.LT, lang.LTE, lang.GT, lang.GTE:
+			return v.compileOperation(a, comparison)
+		}
+	}
+
+	return nil, nil, errors.Wrapf(ErrInvalidParamType, "unsupported argument %s", arg.String())
+}
+
+// compileOperation compiles an arithmetic or comparison argument, folding it when both operands are constant.
+func (v *Validator) compileOperation(exp *lang.BinaryExpr, eval func(op lang.Token, lhs, rhs interface{}) (interface{}, error)) (interface{}, argFunc, error) {
+	lhs, lresolve, err := v.compileArg(exp.LHS)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rhs, rresolve, err := v.compileArg(exp.RHS)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	op := exp.Op
+	if lresolve == nil && rresolve == nil {
+		res, err := eval(op, lhs, rhs)
+		return res, nil, err
+	}
+
+	return nil, func(sc *scope) (interface{}, error) {
+		l, r := lhs, rhs
+		if lresolve != nil {
+			var err error
+			if l, err = lresolve(sc); err != nil {
+				return nil, err
+			}
+		}
+		if rresolve != nil {
+			var err error
+			if r, err = rresolve(sc); err != nil {
+				return nil, err
+			}
+		}
+
+		return eval(op, l, r)
+	}, nil
+}
+
+// valueInterface returns the value held by `val`, or nil if `val` is the zero Value.
+func valueInterface(val reflect.Value) interface{} {
+	if !val.IsValid() {
+		return nil
+	}
+
+	return val.Interface()
+}
+
+// isEmpty reports whether `val` is a zero value, as defined by `Nil`.
+func isEmpty(val reflect.Value) bool {
+	if !val.IsValid() {
+		return true
+	}
+
+	return Nil(val.Interface()) == nil
+}
+
+// indirectInterface returns the value held by an interface value.
+func indirectInterface(val reflect.Value) reflect.Value {
+	for val.Kind() == reflect.Interface && !val.IsNil() {
+		val = val.Elem()
+	}
+
+	return val
+}
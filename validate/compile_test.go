@@ -0,0 +1,130 @@
+package validate_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/olivoil/pkg/validate"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile(t *testing.T) {
+	program, err := validate.Compile("required,each(whitelist('tom','dan'))")
+	assert.NoError(t, err)
+	assert.Equal(t, "required() AND EACH(whitelist('tom', 'dan'))", program.String())
+
+	assert.NoError(t, program.Check([]string{"tom", "dan"}))
+	assert.Error(t, program.Check([]string{"tom", "mike"}))
+	assert.Error(t, program.Check([]string(nil)))
+}
+
+func TestCompile_Errors(t *testing.T) {
+	_, err := validate.Compile("required,")
+	assert.Error(t, err)
+
+	_, err = validate.Compile("nil|unknown")
+	assert.Equal(t, validate.ErrUnknownValidationFunction, errors.Cause(err))
+
+	_, err = validate.Compile("whitelist(required)")
+	assert.Equal(t, validate.ErrInvalidParamType, errors.Cause(err))
+
+	_, err = validate.Compile("required + email")
+	assert.Equal(t, validate.ErrUnknownExpression, errors.Cause(err))
+
+	_, err = validate.Compile("lte(1 / 0)")
+	assert.Equal(t, validate.ErrInvalidParamType, errors.Cause(err))
+
+	_, err = validate.Compile("lte('a' * 2)")
+	assert.Equal(t, validate.ErrInvalidParamType, errors.Cause(err))
+}
+
+func TestCompile_Arithmetic(t *testing.T) {
+	tests := map[string]struct {
+		rule  string
+		valid interface{}
+		fail  interface{}
+	}{
+		"integers":          {rule: "lte(2 + 3 * 4)", valid: 14, fail: 15},
+		"integer division":  {rule: "lt(7 / 2)", valid: 2, fail: 3},
+		"numbers":           {rule: "lte((1 + 0.5) * 2)", valid: 3.0, fail: 3.1},
+		"negative":          {rule: "gte(-1 - 1)", valid: -2, fail: -3},
+		"durations":         {rule: "lte(1h - 2 * 15m)", valid: 30 * time.Minute, fail: 31 * time.Minute},
+		"scaled durations":  {rule: "gt(1h / 4)", valid: 16 * time.Minute, fail: 15 * time.Minute},
+		"duration literals": {rule: "lt(1h)", valid: 59 * time.Minute, fail: time.Hour},
+	}
+
+	for title, tc := range tests {
+		t.Run(title, func(t *testing.T) {
+			program, err := validate.Compile(tc.rule)
+			if assert.NoError(t, err) {
+				assert.NoError(t, program.Check(tc.valid))
+				assert.Error(t, program.Check(tc.fail))
+			}
+		})
+	}
+}
+
+func TestProgram_Check_Errors(t *testing.T) {
+	program, err := validate.Compile("len(3)")
+	assert.NoError(t, err)
+
+	err = program.Check("ab")
+	if assert.IsType(t, validate.Errors{}, err) {
+		errs := err.(validate.Errors)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "len(3)", errs[0].Validation)
+	}
+}
+
+func TestCompile_Comparisons(t *testing.T) {
+	tests := map[string]struct {
+		rule  string
+		valid []interface{}
+		fail  []interface{}
+	}{
+		"range":     {rule: "> 0, <= 10", valid: []interface{}{1, 10, 0.5}, fail: []interface{}{0, 11, -1.5}},
+		"equal":     {rule: "== 'x' | == 'y'", valid: []interface{}{"x", "y"}, fail: []interface{}{"z", ""}},
+		"not equal": {rule: "!= 3", valid: []interface{}{2, 3.5}, fail: []interface{}{3, 3.0}},
+		"duration":  {rule: "< 1h / 2", valid: []interface{}{29 * time.Minute}, fail: []interface{}{time.Hour}},
+		"each":      {rule: "each(>= 'b')", valid: []interface{}{[]string{"b", "c"}}, fail: []interface{}{[]string{"b", "a"}}},
+	}
+
+	for title, tc := range tests {
+		t.Run(title, func(t *testing.T) {
+			program, err := validate.Compile(tc.rule)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, v := range tc.valid {
+				assert.NoError(t, program.Check(v), "%v", v)
+			}
+			for _, v := range tc.fail {
+				assert.Error(t, program.Check(v), "%v", v)
+			}
+		})
+	}
+
+	program, err := validate.Compile("> 1")
+	assert.NoError(t, err)
+
+	err = program.Check("abc")
+	if assert.IsType(t, validate.Errors{}, err) {
+		assert.Equal(t, validate.ErrIncompatibleFieldType, errors.Cause(err.(validate.Errors)[0].Err))
+	}
+}
+
+func BenchmarkProgram_Check(b *testing.B) {
+	program, err := validate.Compile("nil or whitelist(1,3,5,7)")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := program.Check(3); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
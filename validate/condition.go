@@ -0,0 +1,282 @@
+package validate
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/olivoil/pkg/validate/internal/lang"
+	"github.com/pkg/errors"
+)
+
+// condFunc evaluates a condition within a scope.
+type condFunc func(sc *scope) (bool, error)
+
+func (v *Validator) compileWhenExpr(exp *lang.WhenExpr) (checkFunc, error) {
+	cond, err := v.compileCondition(exp.Cond)
+	if err != nil {
+		return nil, errors.Wrapf(err, "in %s", exp.String())
+	}
+
+	check, err := v.compileExpr(exp.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	tracer, rule := v.tracer, exp.String()
+	return func(sc *scope, val reflect.Value) error {
+		ok, err := cond(sc)
+		if err != nil {
+			return sc.unresolved(rule, "when", val, err)
+		}
+
+		if !ok {
+			if tracer != nil {
+				tracer.Trace(Event{Kind: ShortCircuit, Path: sc.path, Rule: rule, Value: valueInterface(val)})
+			}
+			return nil
+		}
+
+		return check(sc, val)
+	}, nil
+}
+
+// compileCondition compiles the condition of a `when` expression.
+// Logical operators short-circuit, any other argument holds when it is true or not empty.
+func (v *Validator) compileCondition(expr lang.Expr) (condFunc, error) {
+	switch e := expr.(type) {
+	case *lang.ParenExpr:
+		return v.compileCondition(e.Expr)
+	case *lang.NegativeExpr:
+		cond, err := v.compileCondition(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(sc *scope) (bool, error) {
+			ok, err := cond(sc)
+			return !ok, err
+		}, nil
+	case *lang.BinaryExpr:
+		if e.Op != lang.AND && e.Op != lang.OR {
+			break
+		}
+
+		lhs, err := v.compileCondition(e.LHS)
+		if err != nil {
+			return nil, err
+		}
+
+		rhs, err := v.compileCondition(e.RHS)
+		if err != nil {
+			return nil, err
+		}
+
+		op := e.Op
+		return func(sc *scope) (bool, error) {
+			ok, err := lhs(sc)
+			if err != nil || ok == (op == lang.OR) {
+				return ok, err
+			}
+
+			return rhs(sc)
+		}, nil
+	}
+
+	val, resolve, err := v.compileArg(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolve == nil {
+		ok := truthy(val)
+		return func(sc *scope) (bool, error) { return ok, nil }, nil
+	}
+
+	return func(sc *scope) (bool, error) {
+		i, err := resolve(sc)
+		if err != nil {
+			return false, err
+		}
+
+		return truthy(i), nil
+	}, nil
+}
+
+// comparisonCodes identifies comparisons in message catalogs, like the validations comparing values.
+var comparisonCodes = map[lang.Token]string{
+	lang.EQ:  "eq",
+	lang.NEQ: "ne",
+	lang.LT:  "lt",
+	lang.LTE: "lte",
+	lang.GT:  "gt",
+	lang.GTE: "gte",
+}
+
+// compileComparisonExpr compiles an infix comparison, whose left-hand side is the value being validated.
+func (v *Validator) compileComparisonExpr(exp *lang.ComparisonExpr) (checkFunc, error) {
+	param, resolve, err := v.compileArg(exp.Expr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "in %s", exp.String())
+	}
+
+	op, validation, tracer := exp.Op, exp.String(), v.tracer
+	return func(sc *scope, val reflect.Value) error {
+		// comparisons do not apply to the missing fields of documents.
+		if sc.missing(val) {
+			return nil
+		}
+
+		arg := param
+		if resolve != nil {
+			var err error
+			if arg, err = resolve(sc); err != nil {
+				return sc.unresolved(validation, comparisonCodes[op], val, err)
+			}
+		}
+
+		i := valueInterface(val)
+		res, err := comparison(op, i, arg)
+		if err != nil {
+			err = errors.Wrapf(ErrIncompatibleFieldType, "cannot compare %v and %v", i, arg)
+		} else if !res.(bool) {
+			err = &ComparisonError{Value: i, Op: comparisonCodes[op], Bound: arg}
+		}
+
+		if tracer != nil {
+			tracer.Trace(Event{Kind: CallResult, Path: sc.path, Rule: validation, Value: i, Args: []interface{}{arg}, Err: err})
+		}
+		if err != nil {
+			e := sc.error(validation, err)
+			e.Code, e.Args, e.Value = comparisonCodes[op], []interface{}{arg}, i
+			return e
+		}
+
+		return nil
+	}, nil
+}
+
+// comparison evaluates `lhs op rhs` for the comparison operators.
+// Equality applies to any values, ordering to numbers, integers, strings, durations and times.
+func comparison(op lang.Token, lhs, rhs interface{}) (interface{}, error) {
+	switch op {
+	case lang.EQ:
+		return equal(lhs, rhs), nil
+	case lang.NEQ:
+		return !equal(lhs, rhs), nil
+	}
+
+	cmp, err := order(lhs, rhs)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case lang.LT:
+		return cmp < 0, nil
+	case lang.LTE:
+		return cmp <= 0, nil
+	case lang.GT:
+		return cmp > 0, nil
+	case lang.GTE:
+		return cmp >= 0, nil
+	}
+
+	return nil, errors.Wrapf(ErrUnknownExpression, "unknown operator %s", op)
+}
+
+// equal reports whether `lhs` and `rhs` hold the same value, regardless of their numeric types.
+func equal(lhs, rhs interface{}) bool {
+	if cmp, err := order(lhs, rhs); err == nil {
+		return cmp == 0
+	}
+
+	return reflect.DeepEqual(normalized(lhs), normalized(rhs))
+}
+
+// order returns -1, 0 or +1 when `lhs` is less than, equal to or greater than `rhs`.
+func order(lhs, rhs interface{}) (int, error) {
+	l, r := normalized(lhs), normalized(rhs)
+
+	switch a := l.(type) {
+	case int64:
+		switch b := r.(type) {
+		case int64:
+			return compareInt64(a, b), nil
+		case float64:
+			return compareFloat64(float64(a), b), nil
+		}
+	case float64:
+		switch b := r.(type) {
+		case int64:
+			return compareFloat64(a, float64(b)), nil
+		case float64:
+			return compareFloat64(a, b), nil
+		}
+	case string:
+		if b, ok := r.(string); ok {
+			return strings.Compare(a, b), nil
+		}
+	case time.Duration:
+		if b, ok := r.(time.Duration); ok {
+			return compareInt64(int64(a), int64(b)), nil
+		}
+	case time.Time:
+		if b, ok := r.(time.Time); ok {
+			switch {
+			case a.Before(b):
+				return -1, nil
+			case a.After(b):
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+
+	return 0, errors.Wrapf(ErrInvalidParamType, "cannot compare %v and %v", lhs, rhs)
+}
+
+// compareInt64 returns -1, 0 or +1 when `a` is less than, equal to or greater than `b`.
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareFloat64 returns -1, 0 or +1 when `a` is less than, equal to or greater than `b`.
+func compareFloat64(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// normalized returns the normalized value of `i`, or `i` itself if it cannot be normalized.
+func normalized(i interface{}) interface{} {
+	if n, err := normalize(i); err == nil {
+		return n
+	}
+
+	return i
+}
+
+// truthy reports whether a condition operand holds: booleans hold when true, other values when not empty.
+func truthy(i interface{}) bool {
+	if b, ok := i.(bool); ok {
+		return b
+	}
+
+	return !isZero(i)
+}
+
+// isZero reports whether `i` is nil or a zero value, as defined by `Nil`.
+func isZero(i interface{}) bool {
+	return i == nil || Nil(i) == nil
+}
@@ -0,0 +1,184 @@
+package validate
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/olivoil/pkg/validate/internal/lang"
+)
+
+// Schema maps the paths of the fields of a document to their rules,
+// i.e. `Schema{"name": "required,max(15)", "contacts": "each(email|phone)"}`.
+type Schema map[string]string
+
+// Map validates the fields of a document `doc`, such as decoded JSON, against the rules of `schema`.
+func Map(doc interface{}, schema Schema) error {
+	return defaultValidator.Map(doc, schema)
+}
+
+// MapCtx validates the fields of a document `doc` against the rules of `schema`, with a context.
+func MapCtx(ctx context.Context, doc interface{}, schema Schema) error {
+	return defaultValidator.MapCtx(ctx, doc, schema)
+}
+
+// Map validates the fields of a document `doc`, made of maps and slices such as decoded JSON,
+// against the rules of `schema`. Paths are written like the paths of bound params (i.e. `address.city` or `items[0]`),
+// and a path without index applies to every item of a slice, i.e. `items.price` validates the price of every item.
+// Missing fields are absent: `required` and its variants fail on them, `nil` passes, and other validations,
+// comparisons and `each`, `keys` or `values` do not apply to them.
+//
+// Bound params resolve inside the document: `$.` from the object holding the field, `$$.` from the document itself,
+// and `^.` from the objects enclosing it. Params whose path does not resolve are nil.
+func (v *Validator) Map(doc interface{}, schema Schema) error {
+	return v.MapCtx(context.Background(), doc, schema)
+}
+
+// MapCtx validates the fields of a document `doc` against the rules of `schema`, with a context.
+// The context is passed to context validations, and validation stops with the context's error when it is done.
+func (v *Validator) MapCtx(ctx context.Context, doc interface{}, schema Schema) error {
+	// nil documents, and nil pointers to documents, are empty.
+	root := reflect.ValueOf(doc)
+	for root.Kind() == reflect.Ptr {
+		if root.IsNil() {
+			root = reflect.Zero(root.Type().Elem())
+			continue
+		}
+		root = root.Elem()
+	}
+	if !root.IsValid() {
+		root = emptyDocument
+	}
+	if root.Kind() != reflect.Map {
+		return ErrInvalidParamType
+	}
+
+	// validate fields in a stable order.
+	paths := make([]string, 0, len(schema))
+	for path := range schema {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var errs Errors
+	for _, path := range paths {
+		rule := schema[path]
+		if rule == "" && v.validationRuleRequired {
+			return ErrMissingValidationRule
+		}
+		if rule == "" || rule == "-" {
+			continue
+		}
+
+		segments, err := lang.ParsePath(path)
+		if err != nil {
+			return err
+		}
+
+		program, err := v.program(rule)
+		if err != nil {
+			return err
+		}
+
+		for _, field := range selectFields(root, nil, nil, segments, nil) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			sc := &scope{ctx: ctx, path: field.path, structs: field.objects, rule: program.rule, absent: true}
+			if errs, err = appendError(errs, program.check(sc, field.value)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// absentValidations validate the missing fields of documents, by name.
+var absentValidations = map[string]func(args ...interface{}) error{
+	"nil":              func(args ...interface{}) error { return nil },
+	"required":         func(args ...interface{}) error { return Required(nil) },
+	"required_if":      func(args ...interface{}) error { return RequiredIf(nil, args...) },
+	"required_with":    func(args ...interface{}) error { return RequiredWith(nil, args...) },
+	"required_without": func(args ...interface{}) error { return RequiredWithout(nil, args...) },
+}
+
+// validateAbsent validates a missing field of a document with the validation `name`.
+// Validations other than `nil` and `required` and its variants do not apply.
+func validateAbsent(name string, args []interface{}) error {
+	if validate, ok := absentValidations[name]; ok {
+		return validate(args...)
+	}
+
+	return nil
+}
+
+// emptyDocument stands for missing objects of a document.
+var emptyDocument = reflect.ValueOf(map[string]interface{}(nil))
+
+// docField is a field of a document selected by a Schema path.
+type docField struct {
+	path Path
+	// value of the field, the zero Value if it is missing.
+	value reflect.Value
+	// objects holds the objects enclosing the field, from the root of the document.
+	objects []reflect.Value
+}
+
+// selectFields appends to `out` the fields located at `segments` below `val`, itself located at `path`
+// and enclosed by `objects`. Segments without index select every item of slices and arrays.
+func selectFields(val reflect.Value, path Path, objects []reflect.Value, segments []lang.Segment, out []docField) []docField {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			val = reflect.Value{}
+			break
+		}
+		val = val.Elem()
+	}
+
+	if len(segments) == 0 {
+		return append(out, docField{path: path, value: val, objects: objects})
+	}
+
+	segment := segments[0]
+	list := val.Kind() == reflect.Slice || val.Kind() == reflect.Array
+
+	switch {
+	case segment.Kind == lang.IndexSegment:
+		item := reflect.Value{}
+		if list && segment.Index < val.Len() {
+			item = val.Index(segment.Index)
+		}
+		return selectFields(item, path.Index(segment.Index), objects, segments[1:], out)
+	case segment.Kind == lang.FieldSegment && list:
+		// the path applies to every item.
+		for i := 0; i < val.Len(); i++ {
+			out = selectFields(val.Index(i), path.Index(i), objects, segments, out)
+		}
+		return out
+	}
+
+	// look up the field in its object, missing objects being empty.
+	object := val
+	if object.Kind() != reflect.Map {
+		object = emptyDocument
+	}
+
+	item := reflect.Value{}
+	if k, ok := mapKey(object.Type().Key(), segment.Name); ok {
+		item = object.MapIndex(k)
+	}
+
+	inner := path.Field(segment.Name)
+	if segment.Kind == lang.KeySegment {
+		inner = path.Key(segment.Name)
+	}
+
+	enclosing := append(objects[:len(objects):len(objects)], object)
+	return selectFields(item, inner, enclosing, segments[1:], out)
+}
@@ -0,0 +1,205 @@
+package validate
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Email options, usable as arguments of the `email` validation (i.e. `email('tld','unquoted')`).
+const (
+	// EmailRequireTLD rejects addresses whose domain has no top-level domain, such as `user@localhost`,
+	// as well as domain literals, such as `user@[127.0.0.1]`.
+	EmailRequireTLD = "tld"
+	// EmailRejectQuoted rejects addresses with a quoted local part, such as `"john doe"@example.com`.
+	EmailRejectQuoted = "unquoted"
+	// EmailAllowIDN accepts internationalized domain names, such as `user@bücher.example`.
+	EmailAllowIDN = "idn"
+)
+
+const (
+	maxEmailLength       = 254
+	maxLocalPartLength   = 64
+	maxDomainLength      = 253
+	maxDomainLabelLength = 63
+)
+
+// emailOptions holds the options of the email validation.
+type emailOptions struct {
+	requireTLD   bool
+	rejectQuoted bool
+	allowIDN     bool
+}
+
+// Email validates `i` is an email address as defined by the addr-spec of RFC 5322,
+// without comments or folding whitespace. See EmailRequireTLD, EmailRejectQuoted and EmailAllowIDN for options.
+func Email(i string, options ...string) error {
+	var opts emailOptions
+	for _, option := range options {
+		switch option {
+		case EmailRequireTLD:
+			opts.requireTLD = true
+		case EmailRejectQuoted:
+			opts.rejectQuoted = true
+		case EmailAllowIDN:
+			opts.allowIDN = true
+		default:
+			return errors.Wrapf(ErrInvalidParamType, "unknown email option %q", option)
+		}
+	}
+
+	if len(i) > maxEmailLength {
+		return &FormatError{Value: i, Format: "email", Reason: fmt.Sprintf("longer than %d characters", maxEmailLength)}
+	}
+
+	at := strings.LastIndex(i, "@")
+	if at < 0 {
+		return &FormatError{Value: i, Format: "email"}
+	}
+
+	if err := validateLocalPart(i[:at], opts); err != nil {
+		return &FormatError{Value: i, Format: "email", Reason: err.Error()}
+	}
+
+	if err := validateEmailDomain(i[at+1:], opts); err != nil {
+		return &FormatError{Value: i, Format: "email", Reason: err.Error()}
+	}
+
+	return nil
+}
+
+// validateLocalPart validates the part of an email address before the `@`.
+func validateLocalPart(local string, opts emailOptions) error {
+	switch {
+	case local == "":
+		return fmt.Errorf("missing local part")
+	case len(local) > maxLocalPartLength:
+		return fmt.Errorf("local part is longer than %d characters", maxLocalPartLength)
+	case len(local) >= 2 && local[0] == '"' && local[len(local)-1] == '"':
+		if opts.rejectQuoted {
+			return fmt.Errorf("quoted local part is not allowed")
+		}
+
+		return validateQuotedString(local[1 : len(local)-1])
+	}
+
+	return validateDotAtom(local)
+}
+
+// validateDotAtom validates a dot-atom, i.e. atoms separated by single dots.
+func validateDotAtom(s string) error {
+	for _, atom := range strings.Split(s, ".") {
+		if atom == "" {
+			return fmt.Errorf("misplaced dot in local part")
+		}
+
+		for _, r := range atom {
+			if !isAtext(r) {
+				return fmt.Errorf("invalid character %q in local part", r)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateQuotedString validates the content of a quoted-string.
+func validateQuotedString(s string) error {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			// quoted-pair
+			if i++; i == len(s) || s[i] < ' ' || s[i] > '~' {
+				return fmt.Errorf("invalid escape in quoted local part")
+			}
+		case c == '"':
+			return fmt.Errorf("unescaped quote in quoted local part")
+		case c < ' ' || c > '~':
+			return fmt.Errorf("invalid character %q in quoted local part", c)
+		}
+	}
+
+	return nil
+}
+
+// isAtext returns true for the characters allowed in an atom.
+func isAtext(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+		strings.ContainsRune("!#$%&'*+-/=?^_`{|}~", r)
+}
+
+// validateEmailDomain validates the part of an email address after the `@`.
+func validateEmailDomain(domain string, opts emailOptions) error {
+	if domain == "" {
+		return fmt.Errorf("missing domain")
+	}
+
+	// domain literal, i.e. `[127.0.0.1]` or `[IPv6:::1]`
+	if domain[0] == '[' && domain[len(domain)-1] == ']' {
+		if opts.requireTLD {
+			return fmt.Errorf("domain literal is not allowed")
+		}
+
+		literal := domain[1 : len(domain)-1]
+		if strings.HasPrefix(literal, "IPv6:") {
+			if ip := net.ParseIP(strings.TrimPrefix(literal, "IPv6:")); ip == nil || ip.To4() != nil {
+				return fmt.Errorf("invalid IPv6 domain literal")
+			}
+			return nil
+		}
+
+		if ip := net.ParseIP(literal); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("invalid domain literal")
+		}
+		return nil
+	}
+
+	if len(domain) > maxDomainLength {
+		return fmt.Errorf("domain is longer than %d characters", maxDomainLength)
+	}
+
+	labels := strings.Split(domain, ".")
+	for _, label := range labels {
+		if err := validateDomainLabel(label, opts); err != nil {
+			return err
+		}
+	}
+
+	if opts.requireTLD {
+		if len(labels) < 2 {
+			return fmt.Errorf("missing top-level domain")
+		}
+
+		if tld := labels[len(labels)-1]; strings.IndexFunc(tld, unicode.IsDigit) >= 0 && !strings.HasPrefix(tld, "xn--") {
+			return fmt.Errorf("invalid top-level domain %q", tld)
+		}
+	}
+
+	return nil
+}
+
+// validateDomainLabel validates a single label of a domain name.
+func validateDomainLabel(label string, opts emailOptions) error {
+	switch {
+	case label == "":
+		return fmt.Errorf("misplaced dot in domain")
+	case len(label) > maxDomainLabelLength:
+		return fmt.Errorf("domain label is longer than %d characters", maxDomainLabelLength)
+	case label[0] == '-' || label[len(label)-1] == '-':
+		return fmt.Errorf("domain label %q starts or ends with a hyphen", label)
+	}
+
+	for _, r := range label {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-':
+		case r > unicode.MaxASCII && opts.allowIDN && (unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)):
+		default:
+			return fmt.Errorf("invalid character %q in domain", r)
+		}
+	}
+
+	return nil
+}
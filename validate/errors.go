@@ -14,8 +14,135 @@ var (
 	ErrIncompatibleFieldType     = errors.New("incompatible field type")
 	ErrUnknownValidationFunction = errors.New("unknown validation function")
 	ErrValidationNotFound        = errors.New("validation not found")
+	ErrMisplacedModifier         = errors.New("misplaced modifier")
+	// ErrValidationUnavailable is wrapped by validations that cannot tell whether a value is valid,
+	// i.e. when a backend is down. Such errors stop the validation and are returned as is.
+	ErrValidationUnavailable = errors.New("validation unavailable")
 )
 
+// PathError is returned when the path of a bound param does not resolve to a value.
+type PathError struct {
+	// Param is the bound param, i.e. `$.Items[0].Price`.
+	Param string
+	// Segment is the step of the path that could not be resolved, i.e. `[0]`.
+	Segment string
+	// Reason describes why the path could not be resolved.
+	Reason string
+	// data is set when the path does not resolve because of the values it goes through,
+	// i.e. an index out of range, rather than because of their types.
+	data bool
+}
+
+func (e *PathError) Error() string {
+	if e.Segment == "" {
+		return fmt.Sprintf("cannot resolve %s: %s", e.Param, e.Reason)
+	}
+
+	return fmt.Sprintf("cannot resolve %s at %s: %s", e.Param, e.Segment, e.Reason)
+}
+
+// comparisonExpectations describes what comparisons expect, by code.
+var comparisonExpectations = map[string]string{
+	"eq":  "equal to",
+	"ne":  "different from",
+	"lt":  "less than",
+	"lte": "less than or equal to",
+	"gt":  "greater than",
+	"gte": "greater than or equal to",
+}
+
+// ComparisonError is returned when a value does not compare as expected to a bound,
+// by `lt`, `lte`, `gt`, `gte` and infix comparisons.
+type ComparisonError struct {
+	// Value is the compared value.
+	Value interface{}
+	// Op is the code of the comparison: `eq`, `ne`, `lt`, `lte`, `gt` or `gte`.
+	Op string
+	// Bound is the value `Value` was compared to.
+	Bound interface{}
+}
+
+func (e *ComparisonError) Error() string {
+	return fmt.Sprintf("expected %v to be %s %v", e.Value, comparisonExpectations[e.Op], e.Bound)
+}
+
+// LengthError is returned by `len` when a value does not have the expected length.
+type LengthError struct {
+	Value    interface{}
+	Expected int
+	Actual   int
+}
+
+func (e *LengthError) Error() string {
+	return fmt.Sprintf("expected %v to have length %d, got %d", e.Value, e.Expected, e.Actual)
+}
+
+// InclusionError is returned by `whitelist` when a value is not one of the allowed values,
+// and by `blacklist` when it is one of the excluded values.
+type InclusionError struct {
+	Value  interface{}
+	Values []interface{}
+	// Excluded indicates the values are excluded, rather than allowed.
+	Excluded bool
+}
+
+func (e *InclusionError) Error() string {
+	if e.Excluded {
+		return fmt.Sprintf("%v in (%v)", e.Value, e.Values)
+	}
+
+	return fmt.Sprintf("%v not in (%v)", e.Value, e.Values)
+}
+
+// MatchError is returned by `match` when a string does not match a regular expression.
+type MatchError struct {
+	Value   string
+	Pattern string
+}
+
+func (e *MatchError) Error() string {
+	return fmt.Sprintf("%v does not match %s", e.Value, e.Pattern)
+}
+
+// NilError is returned by `nil` when a value is not nil, and by `required` and its variants when it is.
+type NilError struct {
+	Value interface{}
+	// Nil indicates the value was expected to be nil.
+	Nil bool
+}
+
+func (e *NilError) Error() string {
+	if e.Nil {
+		return fmt.Sprintf("expected %v to be nil", e.Value)
+	}
+
+	return fmt.Sprintf("expected %v not to be nil", e.Value)
+}
+
+// FormatError is returned by `email`, `phone` and `rfc3339` when a string is not formatted as expected.
+type FormatError struct {
+	Value string
+	// Format is the expected format: `email`, `phone` or `rfc3339`.
+	Format string
+	// Reason explains why the value is not formatted as expected, if known.
+	Reason string
+}
+
+// formatDescriptions describes the formats of FormatError.
+var formatDescriptions = map[string]string{
+	"email":   "an email address",
+	"phone":   "a phone number",
+	"rfc3339": "a time formatted as RFC 3339",
+}
+
+func (e *FormatError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("expected %v to be %s", e.Value, formatDescriptions[e.Format])
+	}
+
+	return fmt.Sprintf("expected %v to be %s: %s", e.Value, formatDescriptions[e.Format], e.Reason)
+}
+
 // Errors holds one or several validation errors.
 type Errors []Error
 
@@ -29,16 +156,60 @@ func (e Errors) Error() string {
 	return strings.Join(msg, "; ")
 }
 
+// appendError adds the validation errors in `err` to `errs`.
+// Other errors are returned as is.
+func appendError(errs Errors, err error) (Errors, error) {
+	switch e := err.(type) {
+	case nil:
+	case Errors:
+		errs = append(errs, e...)
+	case Error:
+		errs = append(errs, e)
+	default:
+		return errs, err
+	}
+
+	return errs, nil
+}
+
 // Error represents a single validation error.
 type Error struct {
 	// Field indicates the name of the field that failed to validate.
 	Field string
-	// Validation indicates the tag of the validation that failed.
+	// Path locates the value that failed to validate, from the validated struct.
+	Path Path
+	// Validation is the part of the rule that failed, i.e. `lte($.User.Balance)`.
 	Validation string
-	// Err is the error from the validation function.
+	// Err is the error from the validation function. Builtin validations return typed errors,
+	// such as *ComparisonError or *LengthError.
 	Err error
+	// Code identifies the validation that failed, i.e. `lte` for `lte($.User.Balance)` or `<= $.User.Balance`,
+	// and `not` for a negation. It is empty for errors of struct-level validations.
+	Code string
+	// Args are the values of the arguments the validation was called with, once bound params are resolved.
+	Args []interface{}
+	// Value is the value that failed to validate.
+	Value interface{}
+	// Rule is the source of the rule that reported the error, i.e. the tag of the field.
+	Rule string
+	// Message is the message or message key attached to the rule in the tag (i.e. `max(15) @'name_too_long'`),
+	// overriding the default message.
+	Message string
 }
 
 func (e Error) Error() string {
-	return fmt.Sprintf("%s failed the '%s' validation: %s", e.Field, e.Validation, e.Err.Error())
+	field := e.Path.String()
+	if field == "" {
+		field = e.Field
+	}
+
+	if e.Message != "" {
+		return fmt.Sprintf("%s: %s", field, e.Message)
+	}
+
+	if e.Err == nil {
+		return fmt.Sprintf("%s failed the '%s' validation", field, e.Validation)
+	}
+
+	return fmt.Sprintf("%s failed the '%s' validation: %s", field, e.Validation, e.Err.Error())
 }
@@ -0,0 +1,97 @@
+package validate
+
+import (
+	"reflect"
+)
+
+// SelfValidator is implemented by structs checking invariants that span several fields.
+// Struct calls Validate after validating the fields of the struct, for the root and every nested struct.
+//
+// Validation errors (Error or Errors) are relative to the struct: their path, or their field when they have no path,
+// is prefixed with the path of the struct. Any other error is reported as a validation error of the struct itself.
+// Validate must not pass its own struct to Struct, which would recurse forever.
+type SelfValidator interface {
+	Validate() error
+}
+
+// StructValidator is a variant of SelfValidator receiving the Validator running the validation,
+// to check values with the same validations, and the path of the struct.
+// Errors are reported like the errors of SelfValidator.
+type StructValidator interface {
+	ValidateStruct(v *Validator, path Path) error
+}
+
+var (
+	selfValidatorType   = reflect.TypeOf((*SelfValidator)(nil)).Elem()
+	structValidatorType = reflect.TypeOf((*StructValidator)(nil)).Elem()
+)
+
+// hooks identifies the struct-level validations implemented by a struct type.
+type hooks struct {
+	validate       bool
+	validateStruct bool
+}
+
+// hooksOf returns the struct-level validations implemented by the struct type `t`,
+// with either a value or a pointer receiver.
+func hooksOf(t reflect.Type) hooks {
+	ptr := reflect.PtrTo(t)
+	return hooks{
+		validate:       ptr.Implements(selfValidatorType),
+		validateStruct: ptr.Implements(structValidatorType),
+	}
+}
+
+// runHooks calls the struct-level validations of the struct `val`, located at `path`.
+func (v *Validator) runHooks(h hooks, path Path, val reflect.Value) Errors {
+	if !h.validate && !h.validateStruct {
+		return nil
+	}
+
+	// call methods on a pointer, so both value and pointer receivers are found.
+	var ptr reflect.Value
+	if val.CanAddr() {
+		ptr = val.Addr()
+	} else {
+		ptr = reflect.New(val.Type())
+		ptr.Elem().Set(val)
+	}
+
+	var errs Errors
+	if h.validate {
+		err := ptr.Interface().(SelfValidator).Validate()
+		errs = append(errs, hookErrors(path, val.Type(), "Validate", err)...)
+	}
+	if h.validateStruct {
+		err := ptr.Interface().(StructValidator).ValidateStruct(v, path)
+		errs = append(errs, hookErrors(path, val.Type(), "ValidateStruct", err)...)
+	}
+
+	return errs
+}
+
+// hookErrors converts the error returned by a struct-level validation of the struct of type `t`,
+// located at `path`, to validation errors.
+func hookErrors(path Path, t reflect.Type, validation string, err error) Errors {
+	errs, err := appendError(nil, err)
+	if err != nil {
+		field := path.Leaf()
+		if field == "" {
+			field = t.Name()
+		}
+
+		return Errors{{Field: field, Path: path, Validation: validation, Err: err}}
+	}
+
+	for i := range errs {
+		relative := errs[i].Path
+		if len(relative) == 0 && errs[i].Field != "" {
+			relative = Path{{Name: errs[i].Field}}
+		}
+
+		full := make(Path, 0, len(path)+len(relative))
+		errs[i].Path = append(append(full, path...), relative...)
+	}
+
+	return errs
+}
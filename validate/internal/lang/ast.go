@@ -26,6 +26,12 @@ func (*BinaryExpr) expr()      {}
 func (*ParenExpr) expr()       {}
 func (*NegativeExpr) expr()    {}
 func (*EachExpr) expr()        {}
+func (*KeysExpr) expr()        {}
+func (*ValuesExpr) expr()      {}
+func (*OmitEmpty) expr()       {}
+func (*WhenExpr) expr()        {}
+func (*ComparisonExpr) expr()  {}
+func (*MessageExpr) expr()     {}
 func (*Call) expr()            {}
 func (*BoundParam) expr()      {}
 func (*StringLiteral) expr()   {}
@@ -42,7 +48,7 @@ type Literal interface {
 
 // BinaryExpr represents an operation between two expressions.
 type BinaryExpr struct {
-	Op  Token // AND/OR
+	Op  Token // AND/OR, or arithmetic and comparison operators in arguments
 	LHS Expr
 	RHS Expr
 }
@@ -76,6 +82,59 @@ type EachExpr struct {
 // String returns a string representation of the parenthesized expression.
 func (e *EachExpr) String() string { return fmt.Sprintf("EACH(%s)", e.Expr.String()) }
 
+// KeysExpr represents an expression that applies to each key in a map.
+type KeysExpr struct {
+	Expr Expr
+}
+
+// String returns a string representation of the keys expression.
+func (e *KeysExpr) String() string { return fmt.Sprintf("KEYS(%s)", e.Expr.String()) }
+
+// ValuesExpr represents an expression that applies to each value in a map.
+type ValuesExpr struct {
+	Expr Expr
+}
+
+// String returns a string representation of the values expression.
+func (e *ValuesExpr) String() string { return fmt.Sprintf("VALUES(%s)", e.Expr.String()) }
+
+// WhenExpr represents an expression that only applies when a condition holds.
+type WhenExpr struct {
+	Cond Expr
+	Expr Expr
+}
+
+// String returns a string representation of the conditional expression.
+func (e *WhenExpr) String() string {
+	return fmt.Sprintf("WHEN(%s, %s)", e.Cond.String(), e.Expr.String())
+}
+
+// ComparisonExpr represents the comparison of the value being validated with an expression (i.e. `<= $.Max`).
+type ComparisonExpr struct {
+	Op   Token
+	Expr Expr
+}
+
+// String returns a string representation of the comparison.
+func (e *ComparisonExpr) String() string { return fmt.Sprintf("%s %s", e.Op.String(), e.Expr.String()) }
+
+// MessageExpr represents an expression whose errors carry a custom message or message key (i.e. `max(15) @'too_long'`).
+type MessageExpr struct {
+	Expr    Expr
+	Message string
+}
+
+// String returns a string representation of the expression and its message.
+func (e *MessageExpr) String() string {
+	return fmt.Sprintf("%s @%s", e.Expr.String(), QuoteString(e.Message))
+}
+
+// OmitEmpty represents the `omitempty` modifier, which skips all other rules when the value is empty.
+type OmitEmpty struct{}
+
+// String returns a string representation of the modifier.
+func (e *OmitEmpty) String() string { return "omitempty" }
+
 // Call represents a function call.
 type Call struct {
 	Name string
@@ -94,13 +153,35 @@ func (c *Call) String() string {
 	return fmt.Sprintf("%s(%s)", c.Name, strings.Join(str, ", "))
 }
 
+// Ref identifies the struct a bound param is resolved from.
+type Ref int
+
+const (
+	// CurrentRef resolves params from the struct being validated (i.e. `$.Currency`).
+	CurrentRef Ref = iota
+	// RootRef resolves params from the root of the validated document (i.e. `$$.Order.Currency`).
+	RootRef
+	// ParentRef resolves params from a struct enclosing the struct being validated (i.e. `^.Currency`).
+	ParentRef
+)
+
 // BoundParam represents the value of a field by name, useful when validating a struct.
 type BoundParam struct {
 	Path string
+	// Ref is the struct the path is resolved from.
+	Ref Ref
+	// Depth is the number of levels above the current struct, for ParentRef (i.e. 2 for `^^.Currency`).
+	Depth int
 }
 
 // String returns a string representation of the bound param.
 func (b *BoundParam) String() string {
+	switch b.Ref {
+	case RootRef:
+		return fmt.Sprintf("$$.%s", b.Path)
+	case ParentRef:
+		return fmt.Sprintf("%s.%s", strings.Repeat("^", b.Depth), b.Path)
+	}
 	return fmt.Sprintf("$.%s", b.Path)
 }
 
@@ -196,3 +277,23 @@ func (r *RegexLiteral) String() string {
 func (r *RegexLiteral) Interface() interface{} {
 	return r.Val
 }
+
+// DefaultGroup is the name of the group of sections without a group name.
+const DefaultGroup = "default"
+
+// Group is the rule of a validation group, i.e. `create:required`.
+type Group struct {
+	// Name of the group, empty for sections of the default group without a group name.
+	Name string
+	Expr Expr
+	// Source is the rule of the group as written, i.e. `required`.
+	Source string
+}
+
+// String returns a string representation of the group.
+func (g Group) String() string {
+	if g.Name == "" {
+		return g.Expr.String()
+	}
+	return fmt.Sprintf("%s:%s", g.Name, g.Expr.String())
+}
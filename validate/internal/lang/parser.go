@@ -24,6 +24,35 @@ func Parse(s string) (Expr, error) {
 	return NewParser(strings.NewReader(s)).Parse(false)
 }
 
+// ParseGroups parses a rule made of sections for validation groups and returns their ASTs.
+// The Source of each group is its section of `s`, without the group name.
+func ParseGroups(s string) ([]Group, error) {
+	groups, spans, err := NewParser(strings.NewReader(s)).parseGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	// positions count line breaks as a single character.
+	src := []rune(newlines.Replace(s))
+	for i, span := range spans {
+		end := span.end
+		if end > len(src) {
+			end = len(src)
+		}
+		groups[i].Source = strings.TrimSpace(string(src[span.start:end]))
+	}
+
+	return groups, nil
+}
+
+// newlines normalizes line breaks the way the scanner reads them.
+var newlines = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// span is the range of positions of a section of the source.
+type span struct {
+	start, end int
+}
+
 // MustParse parses an expression string and returns its AST. Panic on error.
 func MustParse(s string) Expr {
 	expr, err := Parse(s)
@@ -35,6 +64,72 @@ func MustParse(s string) Expr {
 
 // Parse parses an expression.
 func (p *Parser) Parse(call bool) (Expr, error) {
+	return p.parseBinaryExpr(call, 0)
+}
+
+// ParseGroups parses sections separated by semicolons, each applying to a validation group
+// when prefixed with the group name and a colon, or to the default group otherwise
+// (i.e. `max(15);create:required,max(15)`).
+func (p *Parser) ParseGroups() ([]Group, error) {
+	groups, _, err := p.parseGroups()
+	return groups, err
+}
+
+// parseGroups parses sections separated by semicolons, and returns the spans of their rules.
+func (p *Parser) parseGroups() ([]Group, []span, error) {
+	var (
+		groups []Group
+		spans  []span
+	)
+	seen := make(map[string]bool)
+	for {
+		// Read the group name if the section starts with an identifier followed by a colon.
+		var name string
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		start := pos
+		if tok == IDENT {
+			if tok0, pos0, _ := p.Scan(); tok0 == COLON {
+				name, start = lit, pos0+1
+			} else {
+				p.Unscan()
+				p.Unscan()
+			}
+		} else {
+			p.Unscan()
+		}
+
+		// sections without a group name belong to the default group.
+		group := name
+		if group == "" {
+			group = DefaultGroup
+		}
+		if seen[group] {
+			return nil, nil, &ParseError{Message: fmt.Sprintf("duplicate group %q", group), Pos: pos}
+		}
+		seen[group] = true
+
+		expr, err := p.Parse(false)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Sections are separated by semicolons.
+		tok, pos, lit = p.ScanIgnoreWhitespace()
+		groups = append(groups, Group{Name: name, Expr: expr})
+		spans = append(spans, span{start: start, end: pos})
+
+		switch tok {
+		case EOF:
+			return groups, spans, nil
+		case SEMICOLON:
+		default:
+			return nil, nil, newParseError(tokstr(tok, lit), []string{";", "EOF"}, pos)
+		}
+	}
+}
+
+// parseBinaryExpr parses an expression made of operators with a precedence of at least `min`.
+func (p *Parser) parseBinaryExpr(call bool, min int) (Expr, error) {
 	var err error
 
 	// Dummy root node.
@@ -42,7 +137,7 @@ func (p *Parser) Parse(call bool) (Expr, error) {
 
 	// Parse a non-binary expression type to start.
 	// This variable will always be the root of the expression tree.
-	root.RHS, err = p.parseUnaryExpr()
+	root.RHS, err = p.parseOperand(call)
 	if err != nil {
 		return nil, err
 	}
@@ -57,14 +152,14 @@ func (p *Parser) Parse(call bool) (Expr, error) {
 			op = AND
 		}
 
-		if !op.isOperator() {
+		if !op.isOperator() || op.Precedence() < min {
 			p.Unscan()
 			return root.RHS, nil
 		}
 
 		// Otherwise parse the next expression.
 		var rhs Expr
-		if rhs, err = p.parseUnaryExpr(); err != nil {
+		if rhs, err = p.parseOperand(call); err != nil {
 			return nil, err
 		}
 
@@ -84,6 +179,28 @@ func (p *Parser) Parse(call bool) (Expr, error) {
 	}
 }
 
+// parseOperand parses a non-binary expression, followed by the messages attached to it outside of arguments
+// (i.e. `max(15) @'too_long'`).
+func (p *Parser) parseOperand(call bool) (Expr, error) {
+	expr, err := p.parseUnaryExpr()
+	if err != nil || call {
+		return expr, err
+	}
+
+	for {
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != AT {
+			p.Unscan()
+			return expr, nil
+		}
+
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		if tok != STRING {
+			return nil, newParseError(tokstr(tok, lit), []string{"string"}, pos)
+		}
+		expr = &MessageExpr{Expr: expr, Message: lit}
+	}
+}
+
 // parseUnaryExpr parses an non-binary expression.
 func (p *Parser) parseUnaryExpr() (Expr, error) {
 	// If the first token is a LPAREN then parse it as its own grouped expression.
@@ -113,8 +230,44 @@ func (p *Parser) parseUnaryExpr() (Expr, error) {
 	}
 	p.Unscan()
 
-	// If the first token is EACH then parse it as its own grouped expression.
-	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == EACH {
+	// If the first token is a comparison then parse its right-hand side, the left-hand side being the value.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok.isComparison() {
+		expr, err := p.parseBinaryExpr(true, tok.Precedence()+1)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ComparisonExpr{Op: tok, Expr: expr}, nil
+	}
+	p.Unscan()
+
+	// If the first token is a SUB then parse it as a negated operand.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == SUB {
+		expr, err := p.parseUnaryExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		switch lit := expr.(type) {
+		case *NumberLiteral:
+			return &NumberLiteral{Val: -lit.Val}, nil
+		case *IntegerLiteral:
+			return &IntegerLiteral{Val: -lit.Val}, nil
+		case *DurationLiteral:
+			return &DurationLiteral{Val: -lit.Val}, nil
+		}
+		return &BinaryExpr{Op: SUB, LHS: &IntegerLiteral{Val: 0}, RHS: expr}, nil
+	}
+	p.Unscan()
+
+	// If the first token is WHEN then parse a condition followed by the conditional rule.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == WHEN {
+		return p.parseWhen()
+	}
+	p.Unscan()
+
+	// If the first token is EACH, KEYS or VALUES then parse it as its own grouped expression.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == EACH || tok == KEYS || tok == VALUES {
 		tok2, pos2, lit2 := p.ScanIgnoreWhitespace()
 		if tok2 == LPAREN {
 			expr, err := p.Parse(false)
@@ -127,6 +280,12 @@ func (p *Parser) parseUnaryExpr() (Expr, error) {
 				return nil, newParseError(tokstr(tok, lit), []string{")"}, pos)
 			}
 
+			switch tok {
+			case KEYS:
+				return &KeysExpr{Expr: expr}, nil
+			case VALUES:
+				return &ValuesExpr{Expr: expr}, nil
+			}
 			return &EachExpr{Expr: expr}, nil
 		}
 
@@ -137,6 +296,8 @@ func (p *Parser) parseUnaryExpr() (Expr, error) {
 	// Read next token.
 	tok, pos, lit := p.ScanIgnoreWhitespace()
 	switch tok {
+	case OMITEMPTY:
+		return &OmitEmpty{}, nil
 	case IDENT:
 		// return p.parseCall(lit)
 		// If the next immediate token is a left parentheses, parse as function call.
@@ -181,8 +342,23 @@ func (p *Parser) parseUnaryExpr() (Expr, error) {
 			return nil, &ParseError{Message: err.Error(), Pos: pos}
 		}
 		return &RegexLiteral{Val: re}, nil
-	case BOUNDPARAM:
-		return &BoundParam{Path: lit}, nil
+	case BOUNDPARAM, ROOTPARAM, PARENTPARAM:
+		param := &BoundParam{Path: lit}
+		switch tok {
+		case ROOTPARAM:
+			param.Ref = RootRef
+		case PARENTPARAM:
+			param.Ref, param.Depth = ParentRef, len(lit)-len(strings.TrimLeft(lit, "^"))
+			param.Path = strings.TrimPrefix(lit[param.Depth:], ".")
+		}
+
+		if _, err := ParsePath(param.Path); err != nil {
+			if e, ok := err.(*ParseError); ok {
+				e.Pos += pos
+			}
+			return nil, err
+		}
+		return param, nil
 	default:
 		return nil, newParseError(tokstr(tok, lit), []string{"identifier", "string", "number", "bool"}, pos)
 	}
@@ -291,6 +467,35 @@ func (p *Parser) parseCall(name string) (*Call, error) {
 	return &Call{Name: name, Args: args}, nil
 }
 
+// parseWhen parses a conditional expression, i.e. `when($.Method == 'card', required)`.
+// This function assumes the WHEN keyword has been consumed.
+func (p *Parser) parseWhen() (*WhenExpr, error) {
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != LPAREN {
+		return nil, newParseError(tokstr(tok, lit), []string{"("}, pos)
+	}
+
+	// The condition ends at the first comma, the rule extends to the closing parenthesis.
+	cond, err := p.Parse(true)
+	if err != nil {
+		return nil, err
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != COMMA {
+		return nil, newParseError(tokstr(tok, lit), []string{","}, pos)
+	}
+
+	expr, err := p.Parse(false)
+	if err != nil {
+		return nil, err
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != RPAREN {
+		return nil, newParseError(tokstr(tok, lit), []string{")"}, pos)
+	}
+
+	return &WhenExpr{Cond: cond, Expr: expr}, nil
+}
+
 // parseRegex parses a regular expression.
 func (p *Parser) parseRegex() (*RegexLiteral, error) {
 	nextRune := p.peekRune()
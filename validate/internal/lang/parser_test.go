@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/olivoil/pkg/validate/internal/lang"
 	"github.com/stretchr/testify/assert"
@@ -89,6 +90,239 @@ func TestParser_Parse(t *testing.T) {
 				},
 			},
 		},
+		{
+			s: "email,omitempty",
+			expr: &lang.BinaryExpr{
+				Op: lang.AND,
+				LHS: &lang.Call{
+					Name: "email",
+				},
+				RHS: &lang.OmitEmpty{},
+			},
+		},
+		{
+			s: "keys(match(/^[a-z]+$/)),values(gt(0))",
+			expr: &lang.BinaryExpr{
+				Op: lang.AND,
+				LHS: &lang.KeysExpr{
+					Expr: &lang.Call{
+						Name: "match",
+						Args: []lang.Expr{
+							&lang.RegexLiteral{Val: regexp.MustCompile(`^[a-z]+$`)},
+						},
+					},
+				},
+				RHS: &lang.ValuesExpr{
+					Expr: &lang.Call{
+						Name: "gt",
+						Args: []lang.Expr{
+							&lang.IntegerLiteral{Val: 0},
+						},
+					},
+				},
+			},
+		},
+		{
+			s: "lte($.Balance * 1.1, (1 + $.Max) / 2)",
+			expr: &lang.Call{
+				Name: "lte",
+				Args: []lang.Expr{
+					&lang.BinaryExpr{
+						Op:  lang.MUL,
+						LHS: &lang.BoundParam{Path: "Balance"},
+						RHS: &lang.NumberLiteral{Val: 1.1},
+					},
+					&lang.BinaryExpr{
+						Op: lang.DIV,
+						LHS: &lang.ParenExpr{
+							Expr: &lang.BinaryExpr{
+								Op:  lang.ADD,
+								LHS: &lang.IntegerLiteral{Val: 1},
+								RHS: &lang.BoundParam{Path: "Max"},
+							},
+						},
+						RHS: &lang.IntegerLiteral{Val: 2},
+					},
+				},
+			},
+		},
+		{
+			s: "gte($.Start + 1h - 2 * 15m, -1, -$.Min)",
+			expr: &lang.Call{
+				Name: "gte",
+				Args: []lang.Expr{
+					&lang.BinaryExpr{
+						Op: lang.SUB,
+						LHS: &lang.BinaryExpr{
+							Op:  lang.ADD,
+							LHS: &lang.BoundParam{Path: "Start"},
+							RHS: &lang.DurationLiteral{Val: time.Hour},
+						},
+						RHS: &lang.BinaryExpr{
+							Op:  lang.MUL,
+							LHS: &lang.IntegerLiteral{Val: 2},
+							RHS: &lang.DurationLiteral{Val: 15 * time.Minute},
+						},
+					},
+					&lang.IntegerLiteral{Val: -1},
+					&lang.BinaryExpr{
+						Op:  lang.SUB,
+						LHS: &lang.IntegerLiteral{Val: 0},
+						RHS: &lang.BoundParam{Path: "Min"},
+					},
+				},
+			},
+		},
+		{
+			s: "when($.Method == 'card' and $.Total > 0, required, len(16))",
+			expr: &lang.WhenExpr{
+				Cond: &lang.BinaryExpr{
+					Op: lang.AND,
+					LHS: &lang.BinaryExpr{
+						Op:  lang.EQ,
+						LHS: &lang.BoundParam{Path: "Method"},
+						RHS: &lang.StringLiteral{Val: "card"},
+					},
+					RHS: &lang.BinaryExpr{
+						Op:  lang.GT,
+						LHS: &lang.BoundParam{Path: "Total"},
+						RHS: &lang.IntegerLiteral{Val: 0},
+					},
+				},
+				Expr: &lang.BinaryExpr{
+					Op:  lang.AND,
+					LHS: &lang.Call{Name: "required"},
+					RHS: &lang.Call{
+						Name: "len",
+						Args: []lang.Expr{
+							&lang.IntegerLiteral{Val: 16},
+						},
+					},
+				},
+			},
+		},
+		{
+			s: "when($.Total + 1 >= $.Max * 2, required)",
+			expr: &lang.WhenExpr{
+				Cond: &lang.BinaryExpr{
+					Op: lang.GTE,
+					LHS: &lang.BinaryExpr{
+						Op:  lang.ADD,
+						LHS: &lang.BoundParam{Path: "Total"},
+						RHS: &lang.IntegerLiteral{Val: 1},
+					},
+					RHS: &lang.BinaryExpr{
+						Op:  lang.MUL,
+						LHS: &lang.BoundParam{Path: "Max"},
+						RHS: &lang.IntegerLiteral{Val: 2},
+					},
+				},
+				Expr: &lang.Call{Name: "required"},
+			},
+		},
+		{
+			s:   "when($.Method == 'card')",
+			err: "found ), expected , at char 24",
+		},
+		{
+			s: "> 0, <= $.Max * 2 | == 'x'",
+			expr: &lang.BinaryExpr{
+				Op: lang.AND,
+				LHS: &lang.ComparisonExpr{
+					Op:   lang.GT,
+					Expr: &lang.IntegerLiteral{Val: 0},
+				},
+				RHS: &lang.BinaryExpr{
+					Op: lang.OR,
+					LHS: &lang.ComparisonExpr{
+						Op: lang.LTE,
+						Expr: &lang.BinaryExpr{
+							Op:  lang.MUL,
+							LHS: &lang.BoundParam{Path: "Max"},
+							RHS: &lang.IntegerLiteral{Val: 2},
+						},
+					},
+					RHS: &lang.ComparisonExpr{
+						Op:   lang.EQ,
+						Expr: &lang.StringLiteral{Val: "x"},
+					},
+				},
+			},
+		},
+		{
+			s: "each(!= $.Other, >= -1)",
+			expr: &lang.EachExpr{
+				Expr: &lang.BinaryExpr{
+					Op: lang.AND,
+					LHS: &lang.ComparisonExpr{
+						Op:   lang.NEQ,
+						Expr: &lang.BoundParam{Path: "Other"},
+					},
+					RHS: &lang.ComparisonExpr{
+						Op:   lang.GTE,
+						Expr: &lang.IntegerLiteral{Val: -1},
+					},
+				},
+			},
+		},
+		{
+			s:   "required, >",
+			err: "found EOF, expected identifier, string, number, bool at char 12",
+		},
+		{
+			s:   "lte($.Max *)",
+			err: "found ), expected identifier, string, number, bool at char 12",
+		},
+		{
+			s:   "keys required",
+			err: "found required, expected ( at char 6",
+		},
+		{
+			s: `required, max(15) @'name_too_long'`,
+			expr: &lang.BinaryExpr{
+				Op:  lang.AND,
+				LHS: &lang.Call{Name: "required"},
+				RHS: &lang.MessageExpr{
+					Expr:    &lang.Call{Name: "max", Args: []lang.Expr{&lang.IntegerLiteral{Val: 15}}},
+					Message: "name_too_long",
+				},
+			},
+		},
+		{
+			s: `(email | phone) @'Please enter an email or a phone number', > 'a' @'after_a'`,
+			expr: &lang.BinaryExpr{
+				Op: lang.AND,
+				LHS: &lang.MessageExpr{
+					Expr: &lang.ParenExpr{
+						Expr: &lang.BinaryExpr{Op: lang.OR, LHS: &lang.Call{Name: "email"}, RHS: &lang.Call{Name: "phone"}},
+					},
+					Message: "Please enter an email or a phone number",
+				},
+				RHS: &lang.MessageExpr{
+					Expr:    &lang.ComparisonExpr{Op: lang.GT, Expr: &lang.StringLiteral{Val: "a"}},
+					Message: "after_a",
+				},
+			},
+		},
+		{
+			s:   "required @ name",
+			err: "found name, expected string at char 12",
+		},
+		{
+			s:   "lt(3 @'small')",
+			err: "found @, expected ) at char 6",
+		},
+		{
+			s: `whitelist($$.Order.Currency, ^.Currency, ^^.Currency)`,
+			expr: &lang.Call{
+				Name: "whitelist",
+				Args: []lang.Expr{
+					&lang.BoundParam{Path: `Order.Currency`, Ref: lang.RootRef},
+					&lang.BoundParam{Path: `Currency`, Ref: lang.ParentRef, Depth: 1},
+					&lang.BoundParam{Path: `Currency`, Ref: lang.ParentRef, Depth: 2},
+				},
+			},
+		},
 		{
 			s: `required, numeric, range(0, $.Account.Balance)`,
 			expr: &lang.BinaryExpr{
@@ -232,3 +466,100 @@ func errstring(err error) string {
 	}
 	return ""
 }
+
+func TestParsePath(t *testing.T) {
+	var tests = []struct {
+		s        string
+		segments []lang.Segment
+		err      string
+	}{
+		{s: ``},
+		{
+			s: `Account.Balance`,
+			segments: []lang.Segment{
+				{Kind: lang.FieldSegment, Name: "Account"},
+				{Kind: lang.FieldSegment, Name: "Balance"},
+			},
+		},
+		{
+			s: `Items[12].Price`,
+			segments: []lang.Segment{
+				{Kind: lang.FieldSegment, Name: "Items"},
+				{Kind: lang.IndexSegment, Index: 12},
+				{Kind: lang.FieldSegment, Name: "Price"},
+			},
+		},
+		{
+			s: `Labels['env']['a.b']`,
+			segments: []lang.Segment{
+				{Kind: lang.FieldSegment, Name: "Labels"},
+				{Kind: lang.KeySegment, Name: "env"},
+				{Kind: lang.KeySegment, Name: "a.b"},
+			},
+		},
+		{s: `Items[a]`, err: `expected index or quoted key in path at char 6`},
+		{s: `Items[0a]`, err: `bad index in path at char 6`},
+		{s: `Items[0`, err: `expected ] in path at char 6`},
+		{s: `Items[]`, err: `expected index or quoted key in path at char 6`},
+		{s: `Items..Price`, err: `expected field name after . at char 6`},
+		{s: `.Items`, err: `unexpected '.' in path at char 1`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			segments, err := lang.ParsePath(tc.s)
+			assert.Equal(t, tc.err, errstring(err))
+			assert.Equal(t, tc.segments, segments)
+		})
+	}
+}
+
+func TestParseGroups(t *testing.T) {
+	var tests = []struct {
+		s      string
+		groups []lang.Group
+		err    string
+	}{
+		{
+			s: `required`,
+			groups: []lang.Group{
+				{Expr: &lang.Call{Name: "required"}, Source: "required"},
+			},
+		},
+		{
+			s: `max(15); create:required,max(15);update:omitempty`,
+			groups: []lang.Group{
+				{Expr: &lang.Call{Name: "max", Args: []lang.Expr{&lang.IntegerLiteral{Val: 15}}}, Source: "max(15)"},
+				{
+					Name: "create",
+					Expr: &lang.BinaryExpr{
+						Op:  lang.AND,
+						LHS: &lang.Call{Name: "required"},
+						RHS: &lang.Call{Name: "max", Args: []lang.Expr{&lang.IntegerLiteral{Val: 15}}},
+					},
+					Source: "required,max(15)",
+				},
+				{Name: "update", Expr: &lang.OmitEmpty{}, Source: "omitempty"},
+			},
+		},
+		{
+			s: `create:match(/;:/)`,
+			groups: []lang.Group{
+				{Name: "create", Expr: &lang.Call{Name: "match", Args: []lang.Expr{&lang.RegexLiteral{Val: regexp.MustCompile(`;:`)}}}, Source: "match(/;:/)"},
+			},
+		},
+		{s: `create:required;create:nil`, err: `duplicate group "create" at char 17`},
+		{s: `required; default: len(3)`, err: `duplicate group "default" at char 11`},
+		{s: `default: required;len(3)`, err: `duplicate group "default" at char 19`},
+		{s: `required)`, err: `found ), expected ;, EOF at char 9`},
+		{s: `create:`, err: `found EOF, expected identifier, string, number, bool at char 8`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			groups, err := lang.ParseGroups(tc.s)
+			assert.Equal(t, tc.err, errstring(err))
+			assert.Equal(t, tc.groups, groups)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package lang
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SegmentKind identifies the kind of a path Segment.
+type SegmentKind int
+
+const (
+	// FieldSegment selects a struct field by name (i.e. `.Price`).
+	FieldSegment SegmentKind = iota
+	// IndexSegment selects an item by index (i.e. `[0]`).
+	IndexSegment
+	// KeySegment selects a map value by key (i.e. `['env']`).
+	KeySegment
+)
+
+// Segment is a single step of a bound param path.
+type Segment struct {
+	Kind SegmentKind
+	// Name is the field name for FieldSegment, or the key for KeySegment.
+	Name string
+	// Index is the index for IndexSegment.
+	Index int
+}
+
+// String returns a string representation of the segment.
+func (s Segment) String() string {
+	switch s.Kind {
+	case IndexSegment:
+		return fmt.Sprintf("[%d]", s.Index)
+	case KeySegment:
+		return fmt.Sprintf("[%s]", QuoteString(s.Name))
+	}
+	return s.Name
+}
+
+// ParsePath parses the path of a bound param, i.e. `Items[0].Price` or `Labels['env']`.
+func ParsePath(path string) ([]Segment, error) {
+	var segments []Segment
+
+	r := strings.NewReader(path)
+	for r.Len() > 0 {
+		pos := len(path) - r.Len()
+		ch, _, _ := r.ReadRune()
+		switch {
+		case ch == '[':
+			segment, err := parsePathIndex(r, pos)
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, segment)
+		case ch == '.' && len(segments) > 0:
+			name := ScanBareIdent(r)
+			if name == "" || !isIdentFirstChar(rune(name[0])) {
+				return nil, &ParseError{Message: "expected field name after .", Pos: pos}
+			}
+			segments = append(segments, Segment{Kind: FieldSegment, Name: name})
+		case isIdentFirstChar(ch) && len(segments) == 0:
+			_ = r.UnreadRune()
+			segments = append(segments, Segment{Kind: FieldSegment, Name: ScanBareIdent(r)})
+		default:
+			return nil, &ParseError{Message: fmt.Sprintf("unexpected %q in path", ch), Pos: pos}
+		}
+	}
+
+	return segments, nil
+}
+
+// parsePathIndex parses an index or a key, assuming the LBRACKET has been consumed.
+func parsePathIndex(r *strings.Reader, pos int) (Segment, error) {
+	var segment Segment
+
+	ch, _, _ := r.ReadRune()
+	_ = r.UnreadRune()
+	switch {
+	case ch == '\'':
+		key, err := ScanString(r)
+		if err != nil {
+			return segment, &ParseError{Message: "bad key in path", Pos: pos}
+		}
+		segment = Segment{Kind: KeySegment, Name: key}
+	case isDigit(ch):
+		i, err := strconv.Atoi(ScanBareIdent(r))
+		if err != nil {
+			return segment, &ParseError{Message: "bad index in path", Pos: pos}
+		}
+		segment = Segment{Kind: IndexSegment, Index: i}
+	default:
+		return segment, &ParseError{Message: "expected index or quoted key in path", Pos: pos}
+	}
+
+	if ch, _, _ := r.ReadRune(); ch != ']' {
+		return segment, &ParseError{Message: "expected ] in path", Pos: pos}
+	}
+
+	return segment, nil
+}
@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"strings"
 )
 
 // Scanner represents a lexical scanner.
 type Scanner struct {
 	r *reader
+	// prev is the last non-whitespace token scanned.
+	prev Token
 }
 
 // NewScanner returns a new instance of Scanner.
@@ -23,6 +26,15 @@ func NewScanner(r io.Reader) *Scanner {
 // Also returns the literal text read for ident, string and number tokens
 // since these token types can have different literal representations.
 func (s *Scanner) Scan() (tok Token, pos int, lit string) {
+	tok, pos, lit = s.scan()
+	if tok != WS {
+		s.prev = tok
+	}
+	return tok, pos, lit
+}
+
+// scan returns the next token, position and literal from the underlying reader.
+func (s *Scanner) scan() (tok Token, pos int, lit string) {
 	// Read next code point.
 	ch0, pos := s.r.read()
 
@@ -52,19 +64,56 @@ func (s *Scanner) Scan() (tok Token, pos int, lit string) {
 		}
 		return DOT, pos, ""
 	case '/':
+		// A slash following an operand is a division, otherwise it starts a regex.
+		if s.prev.isOperand() {
+			return DIV, pos, ""
+		}
 		s.r.unread()
 		return s.ScanRegex()
+	case '+':
+		return ADD, pos, ""
+	case '-':
+		return SUB, pos, ""
+	case '*':
+		return MUL, pos, ""
 	case '|':
 		return OR, pos, ""
 	case '!':
+		if ch1, _ := s.r.read(); ch1 == '=' {
+			return NEQ, pos, ""
+		}
+		s.r.unread()
 		return NOT, pos, ""
+	case '=':
+		if ch1, _ := s.r.read(); ch1 == '=' {
+			return EQ, pos, ""
+		}
+		s.r.unread()
+	case '<':
+		if ch1, _ := s.r.read(); ch1 == '=' {
+			return LTE, pos, ""
+		}
+		s.r.unread()
+		return LT, pos, ""
+	case '>':
+		if ch1, _ := s.r.read(); ch1 == '=' {
+			return GTE, pos, ""
+		}
+		s.r.unread()
+		return GT, pos, ""
 	case '(':
 		return LPAREN, pos, ""
 	case ')':
 		return RPAREN, pos, ""
 	case ',':
 		return COMMA, pos, ""
-	case '$':
+	case ':':
+		return COLON, pos, ""
+	case ';':
+		return SEMICOLON, pos, ""
+	case '@':
+		return AT, pos, ""
+	case '$', '^':
 		s.r.unread()
 		return s.scanBoundParam()
 	}
@@ -296,7 +345,7 @@ func (s *Scanner) scanBoundParam() (tok Token, pos int, lit string) {
 	for {
 		if ch, _ := s.r.read(); ch == eof {
 			break
-		} else if ch == '$' || ch == '.' || isIdentChar(ch) {
+		} else if ch == '$' || ch == '^' || ch == '.' || ch == '[' || isIdentChar(ch) {
 			s.r.unread()
 			buf.WriteString(ScanBareParam(s.r))
 		} else {
@@ -304,8 +353,18 @@ func (s *Scanner) scanBoundParam() (tok Token, pos int, lit string) {
 			break
 		}
 	}
+	lit = buf.String()
+
+	// Parent params keep their carets, so the parser can tell how many levels up they are.
+	switch {
+	case strings.HasPrefix(lit, "$$"):
+		return ROOTPARAM, pos, strings.TrimPrefix(lit[2:], ".")
+	case strings.HasPrefix(lit, "^"):
+		return PARENTPARAM, pos, lit
+	}
+
 	prefix := regexp.MustCompile(`^\$\.?`)
-	lit = prefix.ReplaceAllString(buf.String(), "")
+	lit = prefix.ReplaceAllString(lit, "")
 
 	return BOUNDPARAM, pos, lit
 }
@@ -313,6 +372,7 @@ func (s *Scanner) scanBoundParam() (tok Token, pos int, lit string) {
 // ScanRegex consumes a token to find escapes
 func (s *Scanner) ScanRegex() (tok Token, pos int, lit string) {
 	_, pos = s.r.curr()
+	s.prev = REGEX
 
 	// Start & end sentinels.
 	start, end := '/', '/'
@@ -541,20 +601,40 @@ func ScanBareIdent(r io.RuneScanner) string {
 
 // ScanBareParam reads bare bound param identifier from a rune reader.
 func ScanBareParam(r io.RuneScanner) string {
-	// Read every param character into the buffer.
+	// Read every param character into the buffer, including indexes and quoted keys in brackets.
 	// Non-param characters and EOF will cause the loop to exit.
 	var buf bytes.Buffer
 
+	brackets, quoted := false, false
 	for {
 		ch, _, err := r.ReadRune()
 		if err != nil {
 			break
-		} else if isIdentChar(ch) || ch == '$' || ch == '.' {
-			_, _ = buf.WriteRune(ch)
-		} else {
+		}
+
+		switch {
+		case quoted:
+			if ch == '\\' {
+				_, _ = buf.WriteRune(ch)
+				if ch, _, err = r.ReadRune(); err != nil {
+					return buf.String()
+				}
+			} else if ch == '\'' {
+				quoted = false
+			}
+		case brackets && ch == '\'':
+			quoted = true
+		case brackets && ch == ']':
+			brackets = false
+		case !brackets && ch == '[':
+			brackets = true
+		case isIdentChar(ch) || ch == '$' || ch == '^' || ch == '.':
+		default:
 			r.UnreadRune()
-			break
+			return buf.String()
 		}
+
+		_, _ = buf.WriteRune(ch)
 	}
 
 	return buf.String()
@@ -20,9 +20,6 @@ func TestScanner_Scan(t *testing.T) {
 		// Special tokens (EOF, ILLEGAL, WS)
 		{s: ``, tok: lang.EOF},
 		{s: `#`, tok: lang.ILLEGAL, lit: `#`},
-		{s: `+`, tok: lang.ILLEGAL, lit: `+`},
-		{s: `-`, tok: lang.ILLEGAL, lit: `-`},
-		{s: `*`, tok: lang.ILLEGAL, lit: `*`},
 		{s: `/`, tok: lang.BADREGEX, lit: ``},
 		{s: `%`, tok: lang.ILLEGAL, lit: `%`},
 		{s: ` `, tok: lang.WS, lit: " "},
@@ -45,10 +42,27 @@ func TestScanner_Scan(t *testing.T) {
 		{s: `NOT`, tok: lang.NOT},
 		{s: `not`, tok: lang.NOT},
 
+		// Arithmetic operators
+		{s: `+`, tok: lang.ADD},
+		{s: `-`, tok: lang.SUB},
+		{s: `*`, tok: lang.MUL},
+
+		// Comparison operators
+		{s: `==`, tok: lang.EQ},
+		{s: `!=`, tok: lang.NEQ},
+		{s: `<`, tok: lang.LT},
+		{s: `<=`, tok: lang.LTE},
+		{s: `>`, tok: lang.GT},
+		{s: `>=`, tok: lang.GTE},
+		{s: `=`, tok: lang.ILLEGAL, lit: `=`},
+
 		// Misc. tokens
 		{s: `(`, tok: lang.LPAREN},
 		{s: `)`, tok: lang.RPAREN},
 		{s: `,`, tok: lang.COMMA},
+		{s: `:`, tok: lang.COLON},
+		{s: `;`, tok: lang.SEMICOLON},
+		{s: `@`, tok: lang.AT},
 
 		// Identifiers
 		{s: `required`, tok: lang.IDENT, lit: `required`},
@@ -87,10 +101,22 @@ func TestScanner_Scan(t *testing.T) {
 		// Keywords
 		{s: `EACH`, tok: lang.EACH},
 		{s: `each(!zero)`, tok: lang.EACH},
+		{s: `KEYS`, tok: lang.KEYS},
+		{s: `keys(required)`, tok: lang.KEYS},
+		{s: `values`, tok: lang.VALUES},
+		{s: `omitempty`, tok: lang.OMITEMPTY},
+		{s: `OMITEMPTY`, tok: lang.OMITEMPTY},
+		{s: `when($.Method == 'card', required)`, tok: lang.WHEN},
 
 		// Bound params
 		{s: `$Title`, tok: lang.BOUNDPARAM, lit: `Title`},
 		{s: `$.Book.Description`, tok: lang.BOUNDPARAM, lit: `Book.Description`},
+		{s: `$.Items[0].Price`, tok: lang.BOUNDPARAM, lit: `Items[0].Price`},
+		{s: `$.Labels['env'])`, tok: lang.BOUNDPARAM, lit: `Labels['env']`},
+		{s: `$.Labels['a]\'b'],`, tok: lang.BOUNDPARAM, lit: `Labels['a]\'b']`},
+		{s: `$$.Order.Currency`, tok: lang.ROOTPARAM, lit: `Order.Currency`},
+		{s: `^.Currency`, tok: lang.PARENTPARAM, lit: `^.Currency`},
+		{s: `^^.Order.Currency`, tok: lang.PARENTPARAM, lit: `^^.Order.Currency`},
 	}
 
 	for i, tc := range tests {
@@ -173,6 +199,17 @@ func TestScanner_Scan_Multi(t *testing.T) {
 			{tok: lang.RPAREN, pos: 7, lit: ``},
 			{tok: lang.EOF, pos: 8, lit: ``},
 		},
+		`lte($.Max/2-1h)`: []multiScanResult{
+			{tok: lang.IDENT, pos: 0, lit: `lte`},
+			{tok: lang.LPAREN, pos: 3, lit: ``},
+			{tok: lang.BOUNDPARAM, pos: 4, lit: `Max`},
+			{tok: lang.DIV, pos: 9, lit: ``},
+			{tok: lang.INTEGER, pos: 10, lit: `2`},
+			{tok: lang.SUB, pos: 11, lit: ``},
+			{tok: lang.DURATION, pos: 12, lit: `1h`},
+			{tok: lang.RPAREN, pos: 14, lit: ``},
+			{tok: lang.EOF, pos: 15, lit: ``},
+		},
 		`lte(15)`: []multiScanResult{
 			{tok: lang.IDENT, pos: 0, lit: `lte`},
 			{tok: lang.LPAREN, pos: 3, lit: ``},
@@ -13,34 +13,53 @@ const (
 
 	literalBeg
 	// IDENT and the following are literal tokens.
-	IDENT      // validation name
-	BOUNDPARAM // $param
-	NUMBER     // 12.3
-	INTEGER    // 12
-	DURATION   // 12h
-	STRING     // "abc"
-	BADSTRING  // "abc
-	TRUE       // true
-	FALSE      // false
-	REGEX      // Regular expressions
-	BADESCAPE  // \q
-	BADREGEX   // `.*
+	IDENT       // validation name
+	BOUNDPARAM  // $param
+	ROOTPARAM   // $$.param
+	PARENTPARAM // ^.param
+	NUMBER      // 12.3
+	INTEGER     // 12
+	DURATION    // 12h
+	STRING      // "abc"
+	BADSTRING   // "abc
+	TRUE        // true
+	FALSE       // false
+	REGEX       // Regular expressions
+	BADESCAPE   // \q
+	BADREGEX    // `.*
 	literalEnd
 
-	LPAREN // (
-	RPAREN // )
-	COMMA  // ,
-	DOT    // .
+	LPAREN    // (
+	RPAREN    // )
+	COMMA     // ,
+	DOT       // .
+	COLON     // :
+	SEMICOLON // ;
+	AT        // @
 
 	operatorBeg
 	// OR and the following are Operators.
 	OR  // |
 	AND // ,
 	NOT // !
+	ADD // +
+	SUB // -
+	MUL // *
+	DIV // /
+	EQ  // ==
+	NEQ // !=
+	LT  // <
+	LTE // <=
+	GT  // >
+	GTE // >=
 	operatorEnd
 
 	keywordBeg
-	EACH // each
+	EACH      // each
+	KEYS      // keys
+	VALUES    // values
+	OMITEMPTY // omitempty
+	WHEN      // when
 	keywordEnd
 )
 
@@ -50,31 +69,50 @@ var tokens = [...]string{
 	WS:      "WS",
 
 	// Literals
-	IDENT:      "IDENT",
-	BOUNDPARAM: "BOUNDPARAM",
-	NUMBER:     "NUMBER",
-	INTEGER:    "INTEGER",
-	DURATION:   "DURATION",
-	STRING:     "STRING",
-	BADSTRING:  "BADSTRING",
-	TRUE:       "TRUE",
-	FALSE:      "FALSE",
-	REGEX:      "REGEX",
-	BADESCAPE:  "BADESCAPE",
-	BADREGEX:   "BADREGEX",
-
-	LPAREN: "(",
-	RPAREN: ")",
-	COMMA:  ",",
-	DOT:    ".",
+	IDENT:       "IDENT",
+	BOUNDPARAM:  "BOUNDPARAM",
+	ROOTPARAM:   "ROOTPARAM",
+	PARENTPARAM: "PARENTPARAM",
+	NUMBER:      "NUMBER",
+	INTEGER:     "INTEGER",
+	DURATION:    "DURATION",
+	STRING:      "STRING",
+	BADSTRING:   "BADSTRING",
+	TRUE:        "TRUE",
+	FALSE:       "FALSE",
+	REGEX:       "REGEX",
+	BADESCAPE:   "BADESCAPE",
+	BADREGEX:    "BADREGEX",
+
+	LPAREN:    "(",
+	RPAREN:    ")",
+	COMMA:     ",",
+	DOT:       ".",
+	COLON:     ":",
+	SEMICOLON: ";",
+	AT:        "@",
 
 	// Operators
 	OR:  "OR",
 	AND: "AND",
 	NOT: "NOT",
+	ADD: "+",
+	SUB: "-",
+	MUL: "*",
+	DIV: "/",
+	EQ:  "==",
+	NEQ: "!=",
+	LT:  "<",
+	LTE: "<=",
+	GT:  ">",
+	GTE: ">=",
 
 	// Keywords
-	EACH: "EACH",
+	EACH:      "EACH",
+	KEYS:      "KEYS",
+	VALUES:    "VALUES",
+	OMITEMPTY: "OMITEMPTY",
+	WHEN:      "WHEN",
 }
 
 var synonyms = map[Token][]string{
@@ -122,6 +160,12 @@ func (tok Token) Precedence() int {
 	case AND:
 	case COMMA:
 		return 3
+	case EQ, NEQ, LT, LTE, GT, GTE:
+		return 4
+	case ADD, SUB:
+		return 5
+	case MUL, DIV:
+		return 6
 	}
 	return 0
 }
@@ -137,6 +181,12 @@ func Lookup(ident string) Token {
 // isOperator returns true for operator tokens.
 func (tok Token) isOperator() bool { return tok > operatorBeg && tok < operatorEnd }
 
+// isOperand returns true for tokens that end an operand, after which `/` is a division rather than a regex.
+func (tok Token) isOperand() bool { return tok.isLiteral() || tok == IDENT || tok == RPAREN }
+
+// isComparison returns true for comparison operator tokens.
+func (tok Token) isComparison() bool { return tok >= EQ && tok <= GTE }
+
 // isKeyword returns true for keyword tokens.
 func (tok Token) isKeyword() bool { return tok > keywordBeg && tok < keywordEnd }
 
@@ -0,0 +1,292 @@
+// Package jsonschema generates JSON Schema documents from the validation rules in struct tags,
+// so clients can check the same constraints as the validate package.
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/olivoil/pkg/validate"
+	"github.com/olivoil/pkg/validate/internal/lang"
+)
+
+// Unsupported describes a rule that cannot be expressed in JSON Schema.
+// Such rules are left out of generated schemas, which are less strict than the validation rules as a result.
+type Unsupported struct {
+	// Path locates the field, as its type name and field name, i.e. `Order.items`.
+	Path string
+	// Rule is the part of the rule that cannot be expressed.
+	Rule string
+	// Reason explains why the rule cannot be expressed.
+	Reason string
+}
+
+// String returns a description of the unsupported rule.
+func (u Unsupported) String() string {
+	return fmt.Sprintf("%s: %s: %s", u.Path, u.Rule, u.Reason)
+}
+
+// Report lists the rules that could not be expressed in a generated schema.
+type Report []Unsupported
+
+// Generator builds JSON Schemas from the types of Go values and the validation rules in their struct tags.
+type Generator struct {
+	tagname   string
+	refPrefix string
+}
+
+// Option customizes a generator.
+type Option func(*Generator)
+
+// WithTagname reads validation rules from the struct tag `name` instead of `validate`.
+func WithTagname(name string) Option {
+	return func(g *Generator) {
+		if name != "" {
+			g.tagname = name
+		}
+	}
+}
+
+// WithRefPrefix references the schemas of named struct types with `prefix` instead of `#/$defs/`,
+// i.e. `#/components/schemas/` when the definitions are stored in an OpenAPI document.
+func WithRefPrefix(prefix string) Option {
+	return func(g *Generator) {
+		g.refPrefix = prefix
+	}
+}
+
+// New returns a generator customized with `options`.
+func New(options ...Option) *Generator {
+	g := &Generator{tagname: "validate", refPrefix: "#/$defs/"}
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generate returns the JSON Schema document of the type of `i`, a value or a reflect.Type.
+func Generate(i interface{}) (*Schema, Report, error) {
+	return New().Generate(i)
+}
+
+// Generate returns the JSON Schema document of the type of `i`, a value or a reflect.Type.
+// Named struct types are defined in `$defs` and referenced, which allows recursive types.
+func (g *Generator) Generate(i interface{}) (*Schema, Report, error) {
+	defs := NewDefinitions()
+	s, report, err := g.Reference(i, defs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.Schema = Draft
+	if len(defs.Schemas) > 0 {
+		s.Defs = defs.Schemas
+	}
+
+	return s, report, nil
+}
+
+// Reference adds the schemas of the named struct types held by the type of `i`, a value or a reflect.Type, to `defs`,
+// and returns the schema of the type, referencing them.
+func (g *Generator) Reference(i interface{}, defs *Definitions) (*Schema, Report, error) {
+	t, ok := i.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(i)
+	}
+	if t == nil {
+		return nil, nil, fmt.Errorf("cannot generate the schema of a nil value")
+	}
+
+	gen := &generation{Generator: g, defs: defs}
+	s, err := gen.typeSchema(t)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, gen.report, nil
+}
+
+// Definitions holds the schemas of named struct types, shared by the schemas referencing them.
+type Definitions struct {
+	// Schemas holds the schemas of struct types by name.
+	Schemas map[string]*Schema
+	// names holds the names of the struct types defined in Schemas.
+	names map[reflect.Type]string
+}
+
+// NewDefinitions returns an empty set of definitions.
+func NewDefinitions() *Definitions {
+	return &Definitions{Schemas: map[string]*Schema{}, names: map[reflect.Type]string{}}
+}
+
+// generation holds the state of the generation of a schema.
+type generation struct {
+	*Generator
+	defs   *Definitions
+	report Report
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// typeSchema returns the schema of values of type `t`, without validation rules.
+func (gen *generation) typeSchema(t reflect.Type) (*Schema, error) {
+	t = indirect(t)
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	}
+
+	switch jsonType(t) {
+	case "array":
+		items, err := gen.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case "object":
+		if t.Kind() == reflect.Map {
+			values, err := gen.typeSchema(t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			return &Schema{Type: "object", AdditionalProperties: values}, nil
+		}
+		if t.Name() == "" {
+			return gen.structSchema(t)
+		}
+		return gen.ref(t)
+	case "":
+		// any value, i.e. for interfaces.
+		return &Schema{}, nil
+	}
+
+	return &Schema{Type: jsonType(t)}, nil
+}
+
+// ref defines the named struct type `t`, once, and returns a reference to its definition.
+func (gen *generation) ref(t reflect.Type) (*Schema, error) {
+	name, ok := gen.defs.names[t]
+	if !ok {
+		name = t.Name()
+		if _, taken := gen.defs.Schemas[name]; taken {
+			name = strings.Replace(t.String(), ".", "_", -1)
+		}
+
+		// register the name before generating the definition, for recursive types.
+		gen.defs.names[t] = name
+		gen.defs.Schemas[name] = &Schema{}
+
+		s, err := gen.structSchema(t)
+		if err != nil {
+			delete(gen.defs.names, t)
+			delete(gen.defs.Schemas, name)
+			return nil, err
+		}
+		gen.defs.Schemas[name] = s
+	}
+
+	return &Schema{Ref: gen.refPrefix + name}, nil
+}
+
+// structSchema returns the schema of the struct type `t`, with the validation rules of its fields.
+func (gen *generation) structSchema(t reflect.Type) (*Schema, error) {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		// filter out private struct fields
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		name, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		// the fields of embedded structs are promoted, as encoding/json does.
+		if field.Anonymous && name == "" {
+			if et := indirect(field.Type); et.Kind() == reflect.Struct {
+				embedded, err := gen.structSchema(et)
+				if err != nil {
+					return nil, err
+				}
+				for n, p := range embedded.Properties {
+					s.Properties[n] = p
+				}
+				s.Required = append(s.Required, embedded.Required...)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property, required, err := gen.fieldSchema(t, field, name)
+		if err != nil {
+			return nil, err
+		}
+
+		s.Properties[name] = property
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	if len(s.Properties) == 0 {
+		s.Properties = nil
+	}
+
+	return s, nil
+}
+
+// fieldSchema returns the schema of the struct field `field` of `owner`, named `name` in JSON,
+// and whether the field is required.
+func (gen *generation) fieldSchema(owner reflect.Type, field reflect.StructField, name string) (*Schema, bool, error) {
+	s, err := gen.typeSchema(field.Type)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rule := field.Tag.Get(gen.tagname)
+	if rule == "" || rule == "-" {
+		return s, false, nil
+	}
+
+	groups, err := lang.ParseGroups(rule)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s.%s: %s", owner.Name(), field.Name, err)
+	}
+
+	// only the rules of the default group apply.
+	for _, group := range groups {
+		if group.Name != "" && group.Name != validate.DefaultGroup {
+			continue
+		}
+
+		f := &fieldRule{gen: gen, path: owner.Name() + "." + name}
+		constraint, required := f.rule(group.Expr, field.Type)
+		return merge(s, constraint), required, nil
+	}
+
+	return s, false, nil
+}
+
+// jsonName returns the name of a struct field in JSON, or true when the field is skipped.
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+
+	return strings.SplitN(tag, ",", 2)[0], false
+}
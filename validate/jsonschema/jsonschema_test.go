@@ -0,0 +1,190 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/olivoil/pkg/validate/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+type Item struct {
+	SKU      string  `json:"sku" validate:"required,match(/^[A-Z]{3}-[0-9]+$/)"`
+	Quantity int     `json:"quantity" validate:"gt(0),lte(100, 50)"`
+	Price    float64 `json:"price" validate:">= 0"`
+}
+
+type Order struct {
+	ID       string            `json:"id" validate:"len(8)"`
+	Status   string            `json:"status" validate:"whitelist('pending','paid')"`
+	Email    string            `json:"email" validate:"omitempty,email"`
+	Items    []Item            `json:"items" validate:"required,each(required)"`
+	Tags     map[string]string `json:"tags,omitempty" validate:"keys(len(2)),values(required)"`
+	Delay    time.Duration     `json:"delay" validate:"lt(1h) OR nil"`
+	At       time.Time         `json:"at"`
+	Parent   *Order            `json:"parent,omitempty"`
+	Count    *int              `json:"count" validate:"required"`
+	Paid     *bool             `json:"paid" validate:"required"`
+	Internal string            `json:"-" validate:"required"`
+	Notes    string            `validate:"create:required"`
+	private  string
+}
+
+func TestGenerate(t *testing.T) {
+	s, report, err := jsonschema.Generate(Order{})
+	assert.Nil(t, err)
+	assert.Equal(t, jsonschema.Report{
+		{Path: "Order.id", Rule: "len(8)", Reason: "len counts the bytes of strings rather than their characters"},
+		{Path: "Order.tags", Rule: "len(2)", Reason: "len counts the bytes of strings rather than their characters"},
+	}, report)
+
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$ref": "#/$defs/Order",
+		"$defs": {
+			"Order": {
+				"type": "object",
+				"properties": {
+					"id": {"type": "string"},
+					"status": {"type": "string", "enum": ["pending", "paid"]},
+					"email": {"type": "string", "anyOf": [{"maxLength": 0}, {"format": "email"}]},
+					"items": {"type": "array", "items": {"$ref": "#/$defs/Item"}, "minItems": 1},
+					"tags": {
+						"type": "object",
+						"additionalProperties": {"type": "string", "minLength": 1}
+					},
+					"delay": {"type": "integer", "anyOf": [{"exclusiveMaximum": 3600000000000}, {"const": 0}]},
+					"at": {"type": "string", "format": "date-time"},
+					"parent": {"$ref": "#/$defs/Order"},
+					"count": {"type": "integer"},
+					"paid": {"type": "boolean"},
+					"Notes": {"type": "string"}
+				},
+				"required": ["items", "count", "paid"]
+			},
+			"Item": {
+				"type": "object",
+				"properties": {
+					"sku": {"type": "string", "minLength": 1, "pattern": "^[A-Z]{3}-[0-9]+$"},
+					"quantity": {"type": "integer", "exclusiveMinimum": 0, "maximum": 50},
+					"price": {"type": "number", "minimum": 0}
+				},
+				"required": ["sku"]
+			}
+		}
+	}`, marshal(t, s))
+}
+
+func TestGenerate_Unsupported(t *testing.T) {
+	type Payment struct {
+		Method string `json:"method" validate:"whitelist('card','cash')"`
+		Card   string `json:"card" validate:"required_if($.Method, 'card')"`
+		Start  int    `json:"start"`
+		End    int    `json:"end" validate:"gt($.Start) AND lt(100)"`
+		Phone  string `json:"phone" validate:"phone OR len(0)"`
+		Note   string `json:"note" validate:"when($.Start > 0, required)"`
+		Code   string `json:"code" validate:"!(len(3), custom)"`
+	}
+
+	s, report, err := jsonschema.Generate(Payment{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, jsonschema.Report{
+		{Path: "Payment.card", Rule: "required_if($.Method, 'card')", Reason: "the rule depends on other fields"},
+		{Path: "Payment.end", Rule: "gt($.Start)", Reason: "the argument is not a numeric or duration literal"},
+		{Path: "Payment.phone", Rule: "phone()", Reason: "custom validations are not supported"},
+		{Path: "Payment.note", Rule: "WHEN($.Start > 0, required())", Reason: "conditions are not supported"},
+		{Path: "Payment.code", Rule: "(NOT (len(3) AND custom()))", Reason: "the negated rule cannot be fully expressed"},
+	}, report)
+
+	// the rules that can be expressed are kept.
+	assert.JSONEq(t, `{"type": "integer", "exclusiveMaximum": 100}`, marshal(t, s.Defs["Payment"].Properties["end"]))
+	assert.JSONEq(t, `{"type": "string"}`, marshal(t, s.Defs["Payment"].Properties["phone"]))
+	// negations are left out unless they are fully expressed.
+	assert.JSONEq(t, `{"type": "string"}`, marshal(t, s.Defs["Payment"].Properties["code"]))
+}
+
+func TestGenerate_Types(t *testing.T) {
+	type Base struct {
+		ID int64 `json:"id" validate:"required"`
+	}
+
+	type Doc struct {
+		Base
+		Data    []byte                 `json:"data"`
+		Matrix  [][]float32            `json:"matrix"`
+		Any     interface{}            `json:"any"`
+		Enabled *bool                  `json:"enabled" validate:"required"`
+		Inline  struct{ Name string }  `json:"inline"`
+		Extra   map[string]interface{} `json:"extra" validate:"len(1)"`
+	}
+
+	s, report, err := jsonschema.New(jsonschema.WithRefPrefix("#/components/schemas/")).Generate(reflect.TypeOf(&Doc{}))
+	assert.Nil(t, err)
+	assert.Empty(t, report)
+
+	assert.Equal(t, "#/components/schemas/Doc", s.Ref)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer", "not": {"const": 0}},
+			"data": {"type": "string"},
+			"matrix": {"type": "array", "items": {"type": "array", "items": {"type": "number"}}},
+			"any": {},
+			"enabled": {"type": "boolean"},
+			"inline": {"type": "object", "properties": {"Name": {"type": "string"}}},
+			"extra": {"type": "object", "additionalProperties": {}, "minProperties": 1, "maxProperties": 1}
+		},
+		"required": ["id", "enabled"]
+	}`, marshal(t, s.Defs["Doc"]))
+}
+
+func TestGenerate_Messages(t *testing.T) {
+	type Plain struct {
+		SKU  string `json:"sku" validate:"required,match(/^[A-Z]+$/)"`
+		Code string `json:"code" validate:"!(len(3) OR custom)"`
+	}
+	type WithMessages struct {
+		SKU  string `json:"sku" validate:"required @'sku_required',match(/^[A-Z]+$/) @'{{.Field}} is invalid'"`
+		Code string `json:"code" validate:"!(len(3) @'invalid_code' OR custom) @'invalid_code'"`
+	}
+
+	plain, plainReport, err := jsonschema.Generate(Plain{})
+	assert.Nil(t, err)
+	s, report, err := jsonschema.Generate(WithMessages{})
+	assert.Nil(t, err)
+
+	// messages do not affect the schema.
+	assert.JSONEq(t, marshal(t, plain.Defs["Plain"]), marshal(t, s.Defs["WithMessages"]))
+	assert.Len(t, report, len(plainReport))
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	type Broken struct {
+		Name string `validate:"len(1"`
+	}
+
+	_, _, err := jsonschema.Generate(Broken{})
+	assert.NotNil(t, err)
+
+	_, _, err = jsonschema.Generate(nil)
+	assert.NotNil(t, err)
+}
+
+func TestGenerator_WithTagname(t *testing.T) {
+	type User struct {
+		Name string `json:"name" rules:"required"`
+	}
+
+	s, _, err := jsonschema.New(jsonschema.WithTagname("rules")).Generate(User{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"name"}, s.Defs["User"].Required)
+}
+
+func marshal(t *testing.T, i interface{}) string {
+	b, err := json.Marshal(i)
+	assert.Nil(t, err)
+	return string(b)
+}
@@ -0,0 +1,426 @@
+package jsonschema
+
+import (
+	"reflect"
+
+	"github.com/olivoil/pkg/validate/internal/lang"
+)
+
+// fieldRule translates the validation rule of a struct field to schema constraints.
+// Translations return nil when a rule adds no constraint, or cannot be expressed and is reported.
+// Both cases accept any value, so generated schemas are never stricter than the validation rules.
+type fieldRule struct {
+	gen  *generation
+	path string
+}
+
+// unsupported reports that `rule` cannot be expressed, because of `reason`.
+func (f *fieldRule) unsupported(rule lang.Expr, reason string) *Schema {
+	f.gen.report = append(f.gen.report, Unsupported{Path: f.path, Rule: rule.String(), Reason: reason})
+	return nil
+}
+
+// rule returns the constraints of the top-level rule `expr` on values of type `t`, and whether the field is required.
+func (f *fieldRule) rule(expr lang.Expr, t reflect.Type) (*Schema, bool) {
+	var (
+		constraint          *Schema
+		omitempty, required bool
+	)
+
+	for _, term := range conjunction(expr) {
+		switch e := withoutMessage(term).(type) {
+		case *lang.OmitEmpty:
+			omitempty = true
+			continue
+		case *lang.Call:
+			if e.Name == "required" && len(e.Args) == 0 {
+				required = true
+			}
+		}
+
+		constraint = merge(constraint, f.expr(term, t))
+	}
+
+	// empty values skip every other validation, including `required`.
+	if !omitempty || constraint == nil {
+		return constraint, required && !omitempty
+	}
+
+	zero := zeroValue(t)
+	if zero == nil {
+		return f.unsupported(expr, "the empty value of the field cannot be expressed"), false
+	}
+
+	return &Schema{AnyOf: []*Schema{zero, constraint}}, false
+}
+
+// expr returns the constraints of `expr` on values of type `t`.
+func (f *fieldRule) expr(expr lang.Expr, t reflect.Type) *Schema {
+	// on pointers, `required` and `nil` only check whether the pointer is nil, not the value it points to.
+	if c, ok := expr.(*lang.Call); ok && t.Kind() == reflect.Ptr {
+		switch c.Name {
+		case "required":
+			// the field is still listed in the required properties.
+			return nil
+		case "nil":
+			return f.unsupported(c, "the empty value of the field cannot be expressed")
+		}
+	}
+
+	t = indirect(t)
+
+	switch e := expr.(type) {
+	case *lang.ParenExpr:
+		return f.expr(e.Expr, t)
+	case *lang.BinaryExpr:
+		return f.binaryExpr(e, t)
+	case *lang.NegativeExpr:
+		// negating a partial translation would reject values the rule accepts.
+		n := len(f.gen.report)
+		s := f.expr(e.Expr, t)
+		if len(f.gen.report) > n {
+			f.gen.report = f.gen.report[:n]
+			return f.unsupported(e, "the negated rule cannot be fully expressed")
+		}
+		if s == nil {
+			return nil
+		}
+		return &Schema{Not: s}
+	case *lang.EachExpr:
+		if jsonType(t) != "array" {
+			return f.unsupported(e, "each only applies to arrays in JSON")
+		}
+		if items := f.expr(e.Expr, t.Elem()); items != nil {
+			return &Schema{Items: items}
+		}
+		return nil
+	case *lang.KeysExpr:
+		if t.Kind() != reflect.Map {
+			return f.unsupported(e, "keys only applies to objects")
+		}
+		if keys := f.expr(e.Expr, t.Key()); keys != nil {
+			return &Schema{PropertyNames: keys}
+		}
+		return nil
+	case *lang.ValuesExpr:
+		if t.Kind() != reflect.Map {
+			return f.unsupported(e, "values only applies to objects")
+		}
+		if values := f.expr(e.Expr, t.Elem()); values != nil {
+			return &Schema{AdditionalProperties: values}
+		}
+		return nil
+	case *lang.MessageExpr:
+		// messages do not change what is valid.
+		return f.expr(e.Expr, t)
+	case *lang.ComparisonExpr:
+		return f.comparison(e, e.Op, []lang.Expr{e.Expr}, t)
+	case *lang.WhenExpr:
+		return f.unsupported(e, "conditions are not supported")
+	case *lang.OmitEmpty:
+		return f.unsupported(e, "omitempty must apply to the whole rule")
+	case *lang.Call:
+		return f.call(e, t)
+	}
+
+	return f.unsupported(expr, "unknown expression")
+}
+
+// binaryExpr returns the constraints of a logical expression: AND requires both sides, OR either side.
+func (f *fieldRule) binaryExpr(e *lang.BinaryExpr, t reflect.Type) *Schema {
+	switch e.Op {
+	case lang.AND:
+		return merge(f.expr(e.LHS, t), f.expr(e.RHS, t))
+	case lang.OR:
+		lhs, rhs := f.expr(e.LHS, t), f.expr(e.RHS, t)
+		if lhs == nil || rhs == nil {
+			// either side accepts any value.
+			return nil
+		}
+		return &Schema{AnyOf: []*Schema{lhs, rhs}}
+	}
+
+	return f.unsupported(e, "unknown operator "+e.Op.String())
+}
+
+// comparisonOps are the comparison operators of the validations comparing values.
+var comparisonOps = map[string]lang.Token{
+	"lt":  lang.LT,
+	"lte": lang.LTE,
+	"gt":  lang.GT,
+	"gte": lang.GTE,
+}
+
+// call returns the constraints of a validation call.
+func (f *fieldRule) call(c *lang.Call, t reflect.Type) *Schema {
+	if op, ok := comparisonOps[c.Name]; ok {
+		return f.comparison(c, op, c.Args, t)
+	}
+
+	switch c.Name {
+	case "required":
+		if s := nonZeroValue(t); s != nil {
+			return s
+		}
+		// the field is still listed in the required properties.
+		return nil
+	case "nil":
+		if s := zeroValue(t); s != nil {
+			return s
+		}
+		return f.unsupported(c, "the empty value of the field cannot be expressed")
+	case "len":
+		if len(c.Args) != 1 {
+			return f.unsupported(c, "len expects a single argument")
+		}
+		n, ok := c.Args[0].(*lang.IntegerLiteral)
+		if !ok {
+			return f.unsupported(c, "the argument is not an integer literal")
+		}
+
+		l := intPtr(int(n.Val))
+		switch {
+		case t.Kind() == reflect.String && n.Val == 0:
+			return &Schema{MinLength: l, MaxLength: l}
+		case t.Kind() == reflect.String:
+			// minLength and maxLength count characters, and differ from byte lengths on multi-byte strings.
+			return f.unsupported(c, "len counts the bytes of strings rather than their characters")
+		case jsonType(t) == "array":
+			return &Schema{MinItems: l, MaxItems: l}
+		case t.Kind() == reflect.Map:
+			return &Schema{MinProperties: l, MaxProperties: l}
+		}
+		return f.unsupported(c, "the length of the field cannot be expressed")
+	case "match":
+		if len(c.Args) != 1 {
+			return f.unsupported(c, "match expects a single argument")
+		}
+		r, ok := c.Args[0].(*lang.RegexLiteral)
+		if !ok {
+			return f.unsupported(c, "the argument is not a regular expression literal")
+		}
+		return &Schema{Pattern: r.Val.String()}
+	case "whitelist", "blacklist":
+		enum, ok := literals(c.Args)
+		if !ok {
+			return f.unsupported(c, "the arguments are not literals")
+		}
+		if c.Name == "blacklist" {
+			return &Schema{Not: &Schema{Enum: enum}}
+		}
+		return &Schema{Enum: enum}
+	case "email":
+		format := "email"
+		for _, arg := range c.Args {
+			option, ok := arg.(*lang.StringLiteral)
+			if !ok || option.Val != "idn" {
+				return f.unsupported(c, "email options are not supported")
+			}
+			format = "idn-email"
+		}
+		return &Schema{Format: format}
+	case "rfc3339":
+		return &Schema{Format: "date-time"}
+	case "required_if", "required_with", "required_without":
+		return f.unsupported(c, "the rule depends on other fields")
+	}
+
+	return f.unsupported(c, "custom validations are not supported")
+}
+
+// comparison returns the constraints of the comparison `value op args`, where every argument must hold.
+func (f *fieldRule) comparison(expr lang.Expr, op lang.Token, args []lang.Expr, t reflect.Type) *Schema {
+	if op == lang.EQ || op == lang.NEQ {
+		values, ok := literals(args)
+		if !ok {
+			return f.unsupported(expr, "the argument is not a literal")
+		}
+		if op == lang.NEQ {
+			return &Schema{Not: &Schema{Const: values[0]}}
+		}
+		return &Schema{Const: values[0]}
+	}
+
+	if jsonType(t) != "integer" && jsonType(t) != "number" {
+		return f.unsupported(expr, "only numbers and durations can be compared")
+	}
+
+	var bound *float64
+	for _, arg := range args {
+		n, ok := number(arg)
+		if !ok {
+			return f.unsupported(expr, "the argument is not a numeric or duration literal")
+		}
+
+		// keep the most restrictive bound.
+		lower := op == lang.GT || op == lang.GTE
+		if bound == nil || (lower && n > *bound) || (!lower && n < *bound) {
+			bound = floatPtr(n)
+		}
+	}
+
+	switch op {
+	case lang.LT:
+		return &Schema{ExclusiveMaximum: bound}
+	case lang.LTE:
+		return &Schema{Maximum: bound}
+	case lang.GT:
+		return &Schema{ExclusiveMinimum: bound}
+	case lang.GTE:
+		return &Schema{Minimum: bound}
+	}
+
+	return f.unsupported(expr, "unknown operator "+op.String())
+}
+
+// conjunction returns the terms of the top-level conjunction of `expr`.
+func conjunction(expr lang.Expr) []lang.Expr {
+	if e, ok := expr.(*lang.BinaryExpr); ok && e.Op == lang.AND {
+		return append(conjunction(e.LHS), conjunction(e.RHS)...)
+	}
+
+	return []lang.Expr{expr}
+}
+
+// withoutMessage returns `expr` without the messages attached to it.
+func withoutMessage(expr lang.Expr) lang.Expr {
+	for {
+		e, ok := expr.(*lang.MessageExpr)
+		if !ok {
+			return expr
+		}
+		expr = e.Expr
+	}
+}
+
+// literals returns the values of `args`, or false when any of them is not a literal.
+// Durations are expressed as integers, as encoding/json encodes them.
+func literals(args []lang.Expr) ([]interface{}, bool) {
+	if len(args) == 0 {
+		return nil, false
+	}
+
+	values := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		switch l := arg.(type) {
+		case *lang.StringLiteral:
+			values = append(values, l.Val)
+		case *lang.BooleanLiteral:
+			values = append(values, l.Val)
+		case *lang.IntegerLiteral:
+			values = append(values, l.Val)
+		case *lang.NumberLiteral:
+			values = append(values, l.Val)
+		case *lang.DurationLiteral:
+			values = append(values, int64(l.Val))
+		default:
+			return nil, false
+		}
+	}
+
+	return values, true
+}
+
+// number returns the value of a numeric or duration literal.
+func number(arg lang.Expr) (float64, bool) {
+	switch l := arg.(type) {
+	case *lang.IntegerLiteral:
+		return float64(l.Val), true
+	case *lang.NumberLiteral:
+		return l.Val, true
+	case *lang.DurationLiteral:
+		return float64(l.Val), true
+	}
+
+	return 0, false
+}
+
+// zeroValue returns a schema accepting the empty value of type `t`, as defined by the `nil` validation,
+// or nil if it cannot be expressed.
+func zeroValue(t reflect.Type) *Schema {
+	if t = indirect(t); t == timeType {
+		return nil
+	}
+
+	switch jsonType(t) {
+	case "string":
+		return &Schema{MaxLength: intPtr(0)}
+	case "integer", "number":
+		return &Schema{Const: 0}
+	case "boolean":
+		return &Schema{Const: false}
+	case "array":
+		return &Schema{MaxItems: intPtr(0)}
+	case "object":
+		if t.Kind() == reflect.Map {
+			return &Schema{MaxProperties: intPtr(0)}
+		}
+	}
+
+	return nil
+}
+
+// nonZeroValue returns a schema rejecting the empty value of type `t`, as defined by the `required` validation,
+// or nil if it cannot be expressed.
+func nonZeroValue(t reflect.Type) *Schema {
+	if t = indirect(t); t == timeType {
+		return nil
+	}
+
+	switch jsonType(t) {
+	case "string":
+		return &Schema{MinLength: intPtr(1)}
+	case "integer", "number":
+		return &Schema{Not: &Schema{Const: 0}}
+	case "boolean":
+		return &Schema{Const: true}
+	case "array":
+		return &Schema{MinItems: intPtr(1)}
+	case "object":
+		if t.Kind() == reflect.Map {
+			return &Schema{MinProperties: intPtr(1)}
+		}
+	}
+
+	return nil
+}
+
+// jsonType returns the JSON type of values of type `t`.
+func jsonType(t reflect.Type) string {
+	switch t = indirect(t); t {
+	case timeType:
+		return "string"
+	case durationType:
+		return "integer"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string"
+		}
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+
+	return ""
+}
+
+// indirect returns the type pointed to by `t`, through any number of pointers.
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
@@ -0,0 +1,90 @@
+package jsonschema
+
+import (
+	"reflect"
+)
+
+// Draft is the JSON Schema dialect of generated documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema (draft 2020-12), limited to the keywords validation rules translate to.
+type Schema struct {
+	Schema string             `json:"$schema,omitempty"`
+	Ref    string             `json:"$ref,omitempty"`
+	Defs   map[string]*Schema `json:"$defs,omitempty"`
+
+	Type   string        `json:"type,omitempty"`
+	Format string        `json:"format,omitempty"`
+	Enum   []interface{} `json:"enum,omitempty"`
+	Const  interface{}   `json:"const,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	PropertyNames        *Schema            `json:"propertyNames,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+
+	Items    *Schema `json:"items,omitempty"`
+	MinItems *int    `json:"minItems,omitempty"`
+	MaxItems *int    `json:"maxItems,omitempty"`
+
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+
+	AllOf []*Schema `json:"allOf,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	Not   *Schema   `json:"not,omitempty"`
+}
+
+var schemaType = reflect.TypeOf(&Schema{})
+
+// merge returns a schema requiring both `a` and `b`.
+// Keywords are combined in a single schema when they do not conflict, otherwise the schemas are combined with allOf.
+func merge(a, b *Schema) *Schema {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	out := *a
+	va, vb, vo := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem(), reflect.ValueOf(&out).Elem()
+	for i := 0; i < va.NumField(); i++ {
+		fa, fb := va.Field(i), vb.Field(i)
+		name := va.Type().Field(i).Name
+
+		switch {
+		case isZero(fb) || reflect.DeepEqual(fa.Interface(), fb.Interface()):
+		case isZero(fa):
+			vo.Field(i).Set(fb)
+		case fa.Type() == schemaType && name != "Not":
+			// applicators on items, keys and values are merged recursively.
+			vo.Field(i).Set(reflect.ValueOf(merge(fa.Interface().(*Schema), fb.Interface().(*Schema))))
+		case name == "AllOf":
+			vo.Field(i).Set(reflect.AppendSlice(fa, fb))
+		default:
+			return &Schema{AllOf: []*Schema{a, b}}
+		}
+	}
+
+	return &out
+}
+
+// isZero reports whether `v` holds the zero value of its type.
+func isZero(v reflect.Value) bool {
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+// intPtr returns a pointer to `i`.
+func intPtr(i int) *int { return &i }
+
+// floatPtr returns a pointer to `f`.
+func floatPtr(f float64) *float64 { return &f }
@@ -0,0 +1,221 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// DefaultLocale is the locale messages fall back to when a locale has no message for a validation.
+const DefaultLocale = "en"
+
+// MessageData describes a validation error to the templates of message catalogs.
+type MessageData struct {
+	// Code identifies the validation, i.e. `len` for `len(3)`, `gt` for `> 0` or `not` for a negation.
+	Code string
+	// Field is the name of the field that failed to validate.
+	Field string
+	// Path locates the value that failed to validate, i.e. `items[0].price`.
+	Path string
+	// Validation is the rule that failed, i.e. `len(3)`.
+	Validation string
+	// Value is the value that failed to validate.
+	Value interface{}
+	// Args are the arguments the validation was called with.
+	Args []interface{}
+	// Err is the error from the validation function.
+	Err error
+}
+
+// Translator renders the messages of validation errors in a locale.
+type Translator interface {
+	// Translate returns the message of the validation error described by `data`, or false if it has none.
+	Translate(data MessageData) (string, bool)
+}
+
+// Catalog is a Translator rendering text/template messages by validation code,
+// i.e. `{"len": "{{.Field}} must have {{index .Args 0}} characters"}`.
+// Templates can use `join` to list arguments, i.e. `{{join .Args}}`.
+type Catalog struct {
+	templates map[string]*template.Template
+}
+
+// messageFuncs are the functions available to message templates.
+var messageFuncs = template.FuncMap{
+	"join": func(args []interface{}) string {
+		s := make([]string, len(args))
+		for i, arg := range args {
+			s[i] = fmt.Sprint(arg)
+		}
+		return strings.Join(s, ", ")
+	},
+}
+
+// NewCatalog parses the message templates of `messages`, by validation code.
+func NewCatalog(messages map[string]string) (*Catalog, error) {
+	c := &Catalog{templates: make(map[string]*template.Template, len(messages))}
+	for code, message := range messages {
+		t, err := template.New(code).Funcs(messageFuncs).Parse(message)
+		if err != nil {
+			return nil, err
+		}
+		c.templates[code] = t
+	}
+
+	return c, nil
+}
+
+// MustCatalog is like NewCatalog but panics if a template cannot be parsed.
+func MustCatalog(messages map[string]string) *Catalog {
+	c, err := NewCatalog(messages)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return c
+}
+
+// Translate implements Translator.
+func (c *Catalog) Translate(data MessageData) (string, bool) {
+	t, ok := c.templates[data.Code]
+	if !ok {
+		return "", false
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", false
+	}
+
+	return b.String(), true
+}
+
+// English is the catalog of the DefaultLocale, with a message for every builtin validation.
+var English = MustCatalog(map[string]string{
+	"required":         "{{.Field}} is required",
+	"required_if":      "{{.Field}} is required",
+	"required_with":    "{{.Field}} is required",
+	"required_without": "{{.Field}} is required",
+	"nil":              "{{.Field}} must be empty",
+	"not":              "{{.Field}} is invalid",
+	"len":              "{{.Field}} must have a length of {{join .Args}}",
+	"match":            "{{.Field}} has an invalid format",
+	"whitelist":        "{{.Field}} must be one of {{join .Args}}",
+	"blacklist":        "{{.Field}} must not be one of {{join .Args}}",
+	"eq":               "{{.Field}} must be {{join .Args}}",
+	"ne":               "{{.Field}} must not be {{join .Args}}",
+	"lt":               "{{.Field}} must be less than {{join .Args}}",
+	"lte":              "{{.Field}} must be at most {{join .Args}}",
+	"gt":               "{{.Field}} must be greater than {{join .Args}}",
+	"gte":              "{{.Field}} must be at least {{join .Args}}",
+	"rfc3339":          "{{.Field}} must be a date and time",
+	"email":            "{{.Field}} must be an email address",
+	"phone":            "{{.Field}} must be a phone number",
+	"Validate":         "{{.Err}}",
+	"ValidateStruct":   "{{.Err}}",
+})
+
+// translators holds the registered translators by locale.
+var translators = struct {
+	sync.RWMutex
+	locales map[string]Translator
+}{locales: map[string]Translator{DefaultLocale: English}}
+
+// RegisterTranslator renders the messages of `locale`, i.e. `fr` or `fr-CA`, with `t`.
+// Messages missing from a regional locale fall back to its language, then to the DefaultLocale.
+func RegisterTranslator(locale string, t Translator) {
+	translators.Lock()
+	defer translators.Unlock()
+
+	translators.locales[normalizeLocale(locale)] = t
+}
+
+// normalizeLocale returns `locale` in lower case with `-` separators, i.e. `fr-ca` for `fr_CA`.
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.Replace(locale, "_", "-", -1))
+}
+
+// translate returns the message of `data` in `locale`, following the fallbacks of RegisterTranslator.
+func translate(locale string, data MessageData) (string, bool) {
+	locales := []string{normalizeLocale(locale)}
+	if i := strings.Index(locales[0], "-"); i > 0 {
+		locales = append(locales, locales[0][:i])
+	}
+	locales = append(locales, DefaultLocale)
+
+	translators.RLock()
+	defer translators.RUnlock()
+
+	for _, l := range locales {
+		if t, ok := translators.locales[l]; ok {
+			if message, ok := t.Translate(data); ok {
+				return message, true
+			}
+		}
+	}
+
+	// the English messages remain available when the DefaultLocale is replaced.
+	return English.Translate(data)
+}
+
+// Localize returns the message of the error in `locale`, or the message of Error if no translator has one.
+// Errors created outside of rules, i.e. by struct-level validations, are looked up by their Validation.
+// Messages attached to rules are looked up as message keys, and rendered as templates otherwise.
+func (e Error) Localize(locale string) string {
+	data := MessageData{
+		Code:       e.Code,
+		Field:      e.Field,
+		Path:       e.Path.String(),
+		Validation: e.Validation,
+		Value:      e.Value,
+		Args:       e.Args,
+		Err:        e.Err,
+	}
+	if data.Code == "" {
+		data.Code = e.Validation
+	}
+	if data.Field == "" {
+		data.Field = data.Path
+	}
+
+	if e.Message != "" {
+		return localizeMessage(locale, e.Message, data)
+	}
+
+	if message, ok := translate(locale, data); ok {
+		return message
+	}
+
+	return e.Error()
+}
+
+// localizeMessage returns the message attached to a rule in `locale`: the message of the key `message`,
+// or `message` itself rendered as a template.
+func localizeMessage(locale, message string, data MessageData) string {
+	key := data
+	key.Code = message
+	if localized, ok := translate(locale, key); ok {
+		return localized
+	}
+
+	if t, err := template.New(message).Funcs(messageFuncs).Parse(message); err == nil {
+		var b bytes.Buffer
+		if err := t.Execute(&b, data); err == nil {
+			return b.String()
+		}
+	}
+
+	return message
+}
+
+// Localize returns the messages of the errors in `locale`.
+func (e Errors) Localize(locale string) []string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Localize(locale)
+	}
+
+	return messages
+}
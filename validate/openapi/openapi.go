@@ -0,0 +1,68 @@
+// Package openapi generates the component schemas of OpenAPI 3.1 documents from the validation rules of
+// request and response types, so API documentation states the constraints enforced by the validate package.
+package openapi
+
+import (
+	"github.com/olivoil/pkg/validate/jsonschema"
+)
+
+// RefPrefix is the prefix of the references to component schemas.
+const RefPrefix = "#/components/schemas/"
+
+// Components is the `components` object of an OpenAPI document, limited to schemas.
+type Components struct {
+	Schemas map[string]*jsonschema.Schema `json:"schemas,omitempty"`
+}
+
+// Generate returns the components defining the schemas of the types of `types`, values or reflect.Types,
+// and the rules that could not be expressed.
+func Generate(types ...interface{}) (*Components, jsonschema.Report, error) {
+	g := New()
+	for _, i := range types {
+		if _, err := g.Schema(i); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return g.Components(), g.Report(), nil
+}
+
+// Generator collects the component schemas of the types used by the operations of an API.
+// Named struct types are defined once, under their Go name, and referenced wherever they are used.
+type Generator struct {
+	generator *jsonschema.Generator
+	defs      *jsonschema.Definitions
+	report    jsonschema.Report
+}
+
+// New returns a generator customized with `options`. References always point to component schemas.
+func New(options ...jsonschema.Option) *Generator {
+	options = append(options, jsonschema.WithRefPrefix(RefPrefix))
+	return &Generator{
+		generator: jsonschema.New(options...),
+		defs:      jsonschema.NewDefinitions(),
+	}
+}
+
+// Schema adds the schemas of the named struct types held by the type of `i`, a value or a reflect.Type,
+// to the components, and returns the schema of the type to use in a media type or a parameter,
+// i.e. `{"$ref": "#/components/schemas/Order"}`.
+func (g *Generator) Schema(i interface{}) (*jsonschema.Schema, error) {
+	s, report, err := g.generator.Reference(i, g.defs)
+	if err != nil {
+		return nil, err
+	}
+
+	g.report = append(g.report, report...)
+	return s, nil
+}
+
+// Components returns the components defining the schemas added so far.
+func (g *Generator) Components() *Components {
+	return &Components{Schemas: g.defs.Schemas}
+}
+
+// Report returns the rules of the schemas added so far that could not be expressed.
+func (g *Generator) Report() jsonschema.Report {
+	return g.report
+}
@@ -0,0 +1,88 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/olivoil/pkg/validate/jsonschema"
+	"github.com/olivoil/pkg/validate/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+type Address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type CreateUser struct {
+	Name      string    `json:"name" validate:"required,match(/^[a-z]+$/)"`
+	Addresses []Address `json:"addresses" validate:"each(len(1) OR nil)"`
+	Billing   *Address  `json:"billing"`
+	Code      string    `json:"code" validate:"custom"`
+}
+
+type User struct {
+	ID      int64   `json:"id" validate:"gt(0)"`
+	Name    string  `json:"name"`
+	Address Address `json:"address"`
+}
+
+func TestGenerate(t *testing.T) {
+	components, report, err := openapi.Generate(CreateUser{}, &User{})
+	assert.Nil(t, err)
+	assert.Equal(t, jsonschema.Report{
+		{Path: "CreateUser.addresses", Rule: "len(1)", Reason: "the length of the field cannot be expressed"},
+		{Path: "CreateUser.addresses", Rule: "nil()", Reason: "the empty value of the field cannot be expressed"},
+		{Path: "CreateUser.code", Rule: "custom()", Reason: "custom validations are not supported"},
+	}, report)
+
+	b, err := json.Marshal(components)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"schemas": {
+			"Address": {
+				"type": "object",
+				"properties": {"city": {"type": "string", "minLength": 1}},
+				"required": ["city"]
+			},
+			"CreateUser": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string", "minLength": 1, "pattern": "^[a-z]+$"},
+					"addresses": {"type": "array", "items": {"$ref": "#/components/schemas/Address"}},
+					"billing": {"$ref": "#/components/schemas/Address"},
+					"code": {"type": "string"}
+				},
+				"required": ["name"]
+			},
+			"User": {
+				"type": "object",
+				"properties": {
+					"id": {"type": "integer", "exclusiveMinimum": 0},
+					"name": {"type": "string"},
+					"address": {"$ref": "#/components/schemas/Address"}
+				}
+			}
+		}
+	}`, string(b))
+}
+
+func TestGenerator_Schema(t *testing.T) {
+	g := openapi.New()
+
+	s, err := g.Schema([]User{})
+	assert.Nil(t, err)
+	assert.Equal(t, &jsonschema.Schema{Type: "array", Items: &jsonschema.Schema{Ref: "#/components/schemas/User"}}, s)
+
+	// types are defined once.
+	s, err = g.Schema(User{})
+	assert.Nil(t, err)
+	assert.Equal(t, &jsonschema.Schema{Ref: "#/components/schemas/User"}, s)
+	assert.Len(t, g.Components().Schemas, 2)
+
+	_, err = g.Schema(struct {
+		Name string `validate:"len("`
+	}{})
+	assert.NotNil(t, err)
+	assert.Len(t, g.Components().Schemas, 2)
+	assert.Empty(t, g.Report())
+}
@@ -0,0 +1,159 @@
+package validate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/olivoil/pkg/validate/internal/lang"
+)
+
+// StructPartial validates the fields of a struct `i` located at `paths`, skipping the rules of every other field.
+func StructPartial(i interface{}, paths ...string) error {
+	return defaultValidator.StructPartial(i, paths...)
+}
+
+// StructPartialCtx validates the fields of a struct `i` located at `paths`, with a context.
+func StructPartialCtx(ctx context.Context, i interface{}, paths ...string) error {
+	return defaultValidator.StructPartialCtx(ctx, i, paths...)
+}
+
+// StructExcept validates the fields of a struct `i`, skipping the rules of the fields located at `paths`.
+func StructExcept(i interface{}, paths ...string) error {
+	return defaultValidator.StructExcept(i, paths...)
+}
+
+// StructExceptCtx validates the fields of a struct `i`, skipping the fields located at `paths`, with a context.
+func StructExceptCtx(ctx context.Context, i interface{}, paths ...string) error {
+	return defaultValidator.StructExceptCtx(ctx, i, paths...)
+}
+
+// StructPartial validates the fields of a struct `s` located at `paths`, skipping the rules of every other field.
+// Paths use the field names reported in errors, i.e. `Name`, `address.city` or `Items[0].Price`.
+// A path selects every field below it, and a path without index selects every item of a slice, array or map,
+// i.e. `Items.Price` selects the price of every item. Bound params can still reference any field.
+func (v *Validator) StructPartial(s interface{}, paths ...string) error {
+	return v.StructPartialCtx(context.Background(), s, paths...)
+}
+
+// StructPartialCtx validates the fields of a struct `s` located at `paths`, with a context.
+func (v *Validator) StructPartialCtx(ctx context.Context, s interface{}, paths ...string) error {
+	filter, err := newPathFilter(paths, false)
+	if err != nil {
+		return err
+	}
+
+	return v.structCtx(&walk{ctx: ctx, groups: []string{DefaultGroup}, filter: filter, visited: visits{}}, s)
+}
+
+// StructExcept validates the fields of a struct `s`, skipping the rules of the fields located at `paths`
+// and of every field below them. Paths are matched like in StructPartial.
+func (v *Validator) StructExcept(s interface{}, paths ...string) error {
+	return v.StructExceptCtx(context.Background(), s, paths...)
+}
+
+// StructExceptCtx validates the fields of a struct `s`, skipping the fields located at `paths`, with a context.
+func (v *Validator) StructExceptCtx(ctx context.Context, s interface{}, paths ...string) error {
+	filter, err := newPathFilter(paths, true)
+	if err != nil {
+		return err
+	}
+
+	return v.structCtx(&walk{ctx: ctx, groups: []string{DefaultGroup}, filter: filter, visited: visits{}}, s)
+}
+
+// pathFilter selects the values to validate by path.
+type pathFilter struct {
+	patterns [][]lang.Segment
+	// except causes the values matching patterns to be skipped, instead of the others.
+	except bool
+}
+
+// newPathFilter parses the `paths` selecting the values to validate, or to skip with `except`.
+func newPathFilter(paths []string, except bool) (*pathFilter, error) {
+	f := &pathFilter{except: except}
+	for _, path := range paths {
+		segments, err := lang.ParsePath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		f.patterns = append(f.patterns, segments)
+	}
+
+	return f, nil
+}
+
+// check reports whether the rules of the value at `path` run, and whether the values below it are walked.
+// A nil filter selects every value.
+func (f *pathFilter) check(path Path) (run bool, descend bool) {
+	if f == nil {
+		return true, true
+	}
+
+	var full, prefix bool
+	for _, pattern := range f.patterns {
+		switch matchPath(pattern, path) {
+		case matchFull:
+			full = true
+		case matchPrefix:
+			prefix = true
+		}
+	}
+
+	if f.except {
+		return !full, !full
+	}
+
+	return full, full || prefix
+}
+
+// pathMatch is the result of matching a path against a pattern.
+type pathMatch int
+
+const (
+	// matchNone indicates the path is unrelated to the pattern.
+	matchNone pathMatch = iota
+	// matchPrefix indicates the pattern locates a value below the path.
+	matchPrefix
+	// matchFull indicates the path locates the value of the pattern, or a value below it.
+	matchFull
+)
+
+// matchPath matches `path` against the segments of a `pattern`.
+// Indexes and keys of the path are skipped when the pattern has none at their position.
+func matchPath(pattern []lang.Segment, path Path) pathMatch {
+	i := 0
+	for _, elem := range path {
+		if i == len(pattern) {
+			return matchFull
+		}
+
+		seg := pattern[i]
+		switch {
+		case elem.Name != "":
+			if seg.Kind != lang.FieldSegment || seg.Name != elem.Name {
+				return matchNone
+			}
+		case seg.Kind == lang.FieldSegment:
+			// the pattern applies to every item.
+			continue
+		case seg.Kind == lang.IndexSegment && elem.Key == nil:
+			if seg.Index != elem.Index {
+				return matchNone
+			}
+		case seg.Kind == lang.KeySegment && elem.Key != nil:
+			if seg.Name != fmt.Sprint(elem.Key) {
+				return matchNone
+			}
+		default:
+			return matchNone
+		}
+		i++
+	}
+
+	if i == len(pattern) {
+		return matchFull
+	}
+
+	return matchPrefix
+}
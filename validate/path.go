@@ -0,0 +1,80 @@
+package validate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PathElem is a single step in a Path: a struct field, an index in a slice, array or string, or a map key.
+type PathElem struct {
+	// Name of the struct field, empty for indexes and keys.
+	Name string
+	// Index in a slice, array or string.
+	Index int
+	// Key in a map, nil for fields and indexes.
+	Key interface{}
+}
+
+// Path locates a value within the validated value, i.e. `user.address.zip` or `contacts[2]`.
+type Path []PathElem
+
+// Field returns a copy of the path extended with a struct field.
+func (p Path) Field(name string) Path {
+	return p.append(PathElem{Name: name})
+}
+
+// Index returns a copy of the path extended with an index.
+func (p Path) Index(i int) Path {
+	return p.append(PathElem{Index: i})
+}
+
+// Key returns a copy of the path extended with a map key.
+func (p Path) Key(key interface{}) Path {
+	return p.append(PathElem{Key: key})
+}
+
+// append returns a copy of the path extended with `elem`, so paths never share their backing array.
+func (p Path) append(elem PathElem) Path {
+	path := make(Path, len(p), len(p)+1)
+	copy(path, p)
+	return append(path, elem)
+}
+
+// Leaf returns the name of the last struct field in the path.
+func (p Path) Leaf() string {
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i].Name != "" {
+			return p[i].Name
+		}
+	}
+
+	return ""
+}
+
+// String returns the path with fields separated by dots, and indexes and keys in brackets.
+func (p Path) String() string {
+	var b strings.Builder
+	for i, elem := range p {
+		if elem.Key != nil {
+			b.WriteString("[")
+			fmt.Fprint(&b, elem.Key)
+			b.WriteString("]")
+			continue
+		}
+
+		if elem.Name == "" {
+			b.WriteString("[")
+			b.WriteString(strconv.Itoa(elem.Index))
+			b.WriteString("]")
+			continue
+		}
+
+		if i > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(elem.Name)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	minE164Digits = 7
+	maxE164Digits = 15
+)
+
+// phoneRegion holds the dialing conventions of a region.
+type phoneRegion struct {
+	// code is the country calling code.
+	code string
+	// trunk is the national prefix dialed before national numbers, if any.
+	trunk string
+}
+
+// phoneRegions holds the dialing conventions of regions, by ISO 3166-1 alpha-2 code.
+var phoneRegions = map[string]phoneRegion{
+	"AR": {"54", "0"}, "AT": {"43", "0"}, "AU": {"61", "0"}, "BE": {"32", "0"},
+	"BR": {"55", "0"}, "CA": {"1", "1"}, "CH": {"41", "0"}, "CL": {"56", ""},
+	"CN": {"86", "0"}, "CO": {"57", ""}, "CZ": {"420", ""}, "DE": {"49", "0"},
+	"DK": {"45", ""}, "EG": {"20", "0"}, "ES": {"34", ""}, "FI": {"358", "0"},
+	"FR": {"33", "0"}, "GB": {"44", "0"}, "GR": {"30", ""}, "HK": {"852", ""},
+	"HU": {"36", "06"}, "ID": {"62", "0"}, "IE": {"353", "0"}, "IL": {"972", "0"},
+	"IN": {"91", "0"}, "IT": {"39", ""}, "JP": {"81", "0"}, "KE": {"254", "0"},
+	"KR": {"82", "0"}, "MA": {"212", "0"}, "MX": {"52", ""}, "MY": {"60", "0"},
+	"NG": {"234", "0"}, "NL": {"31", "0"}, "NO": {"47", ""}, "NZ": {"64", "0"},
+	"PE": {"51", "0"}, "PH": {"63", "0"}, "PK": {"92", "0"}, "PL": {"48", ""},
+	"PT": {"351", ""}, "RO": {"40", "0"}, "RU": {"7", "8"}, "SA": {"966", "0"},
+	"SE": {"46", "0"}, "SG": {"65", ""}, "TH": {"66", "0"}, "TR": {"90", "0"},
+	"TW": {"886", "0"}, "UA": {"380", "0"}, "US": {"1", "1"}, "VN": {"84", "0"},
+	"ZA": {"27", "0"},
+}
+
+// Phone validates `i` is a phone number in the E.164 format (i.e. `+14155552671`).
+// Spaces, dots, dashes and parentheses between digits are ignored.
+// When a region is given as an ISO 3166-1 alpha-2 code (i.e. `phone('US')`),
+// numbers without a leading `+` are read as national numbers of that region.
+func Phone(i string, region ...string) error {
+	var r *phoneRegion
+	switch len(region) {
+	case 0:
+	case 1:
+		pr, ok := phoneRegions[strings.ToUpper(region[0])]
+		if !ok {
+			return errors.Wrapf(ErrInvalidParamType, "unknown region %q", region[0])
+		}
+		r = &pr
+	default:
+		return errors.Wrap(ErrInvalidParamType, "phone expects at most one region")
+	}
+
+	number, err := e164(i, r)
+	if err != nil {
+		return &FormatError{Value: i, Format: "phone", Reason: err.Error()}
+	}
+
+	if len(number) < minE164Digits+1 || len(number) > maxE164Digits+1 {
+		return &FormatError{Value: i, Format: "phone", Reason: fmt.Sprintf("expected %d to %d digits", minE164Digits, maxE164Digits)}
+	}
+
+	if number[1] == '0' {
+		return &FormatError{Value: i, Format: "phone", Reason: "invalid country code"}
+	}
+
+	return nil
+}
+
+// e164 normalizes a phone number to the E.164 format, reading numbers without a leading `+` as national numbers of region `r`.
+func e164(s string, r *phoneRegion) (string, error) {
+	var buf strings.Builder
+	for i, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			buf.WriteRune(c)
+		case c == '+' && i == 0:
+			buf.WriteRune(c)
+		case c == ' ' || c == '.' || c == '-' || c == '(' || c == ')':
+		default:
+			return "", fmt.Errorf("invalid character %q", c)
+		}
+	}
+
+	number := buf.String()
+	if strings.HasPrefix(number, "+") {
+		return number, nil
+	}
+
+	if r == nil {
+		return "", fmt.Errorf("missing country code")
+	}
+
+	if r.trunk != "" {
+		number = strings.TrimPrefix(number, r.trunk)
+	}
+
+	return "+" + r.code + number, nil
+}
@@ -0,0 +1,106 @@
+package validate
+
+import (
+	"reflect"
+)
+
+// structPlan is the compiled set of rules for a struct type.
+// Plans are built once per type and cached on the Validator.
+type structPlan struct {
+	fields []fieldPlan
+	// hooks are the struct-level validations implemented by the type.
+	hooks hooks
+	// err is the error encountered while building the plan, if any.
+	err error
+}
+
+// fieldPlan holds everything needed to validate a single struct field.
+type fieldPlan struct {
+	// index of the field in the struct.
+	index int
+	// name of the field, as reported in errors.
+	name string
+	// programs are the compiled rules of the field, by validation group.
+	programs map[string]*Program
+	// nested indicates the field holds structs to validate recursively,
+	// directly or through pointers, slices, arrays or map values.
+	nested bool
+}
+
+// plan returns the validation plan for the struct type `t`, building and caching it on first use.
+func (v *Validator) plan(t reflect.Type) *structPlan {
+	c := v.cache()
+	if p, ok := c.plans.Load(t); ok {
+		return p.(*structPlan)
+	}
+
+	p, _ := c.plans.LoadOrStore(t, v.buildPlan(t))
+	return p.(*structPlan)
+}
+
+// buildPlan parses the rules of every exported field of the struct type `t`.
+func (v *Validator) buildPlan(t reflect.Type) *structPlan {
+	p := &structPlan{hooks: hooksOf(t)}
+
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+
+		// filter out private struct fields
+		if structField.PkgPath != "" {
+			continue // Private field
+		}
+
+		rule := structField.Tag.Get(v.tagname)
+
+		// check if rule is missing unintentionally.
+		if rule == "" && v.validationRuleRequired {
+			p.err = ErrMissingValidationRule
+			return p
+		}
+
+		field := fieldPlan{
+			index:  i,
+			name:   structFieldName(structField),
+			nested: holdsStruct(structField.Type) && rule != "-",
+		}
+
+		if rule != "" && rule != "-" {
+			// parse and compile the rule of every group
+			programs, err := v.compileGroups(rule)
+			if err != nil {
+				p.err = err
+				return p
+			}
+
+			field.programs = programs
+		}
+
+		// skip fields with nothing to validate.
+		if len(field.programs) == 0 && !field.nested {
+			continue
+		}
+
+		p.fields = append(p.fields, field)
+	}
+
+	return p
+}
+
+// holdsStruct reports whether values of type `t` are structs, or pointers, slices, arrays or maps of structs.
+// Recursive types without structs, such as `type Tree map[string]Tree`, hold none.
+func holdsStruct(t reflect.Type) bool {
+	seen := map[reflect.Type]bool{}
+	for !seen[t] {
+		seen[t] = true
+		switch t.Kind() {
+		case reflect.Struct:
+			return true
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return false
+		}
+	}
+
+	return false
+}
@@ -1,18 +1,70 @@
 package validate
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/olivoil/pkg/validate/internal/lang"
 )
 
+// DefaultGroup is the validation group of rules without a group name in field tags.
+const DefaultGroup = lang.DefaultGroup
+
 // Struct validates all exported fields in a struct `i` against the rules in field tags.
 func Struct(i interface{}) error {
-	return New().Struct(i)
+	return defaultValidator.Struct(i)
+}
+
+// StructCtx validates all exported fields in a struct `i` against the rules in field tags, with a context.
+func StructCtx(ctx context.Context, i interface{}) error {
+	return defaultValidator.StructCtx(ctx, i)
+}
+
+// StructGroups validates all exported fields in a struct `i` against the rules of the validation `groups` in field tags.
+func StructGroups(i interface{}, groups ...string) error {
+	return defaultValidator.StructGroups(i, groups...)
+}
+
+// StructGroupsCtx validates all exported fields in a struct `i` against the rules of the validation `groups` in field tags,
+// with a context.
+func StructGroupsCtx(ctx context.Context, i interface{}, groups ...string) error {
+	return defaultValidator.StructGroupsCtx(ctx, i, groups...)
 }
 
 // Struct validates all exported fields in a struct `i` against the rules in field tags.
+// Inner structs are validated recursively, including through pointers, slices, arrays and map values.
+// Structs implementing SelfValidator or StructValidator are checked once their fields are validated.
+// Rules are parsed once per struct type and cached on the Validator.
 func (v *Validator) Struct(s interface{}) error {
+	return v.StructCtx(context.Background(), s)
+}
+
+// StructCtx validates all exported fields in a struct `i` against the rules in field tags.
+// The context is passed to context validations, and validation stops with the context's error when it is done.
+func (v *Validator) StructCtx(ctx context.Context, s interface{}) error {
+	return v.StructGroupsCtx(ctx, s, DefaultGroup)
+}
+
+// StructGroups validates all exported fields in a struct `i` against the rules of the validation `groups` in field tags.
+func (v *Validator) StructGroups(s interface{}, groups ...string) error {
+	return v.StructGroupsCtx(context.Background(), s, groups...)
+}
+
+// StructGroupsCtx validates all exported fields in a struct `i` against the rules of the validation `groups` in field tags,
+// with a context. Rules of several groups run in the order of `groups`, and the DefaultGroup runs when no group is given.
+// Rules without a group name only run when the DefaultGroup is one of `groups`.
+func (v *Validator) StructGroupsCtx(ctx context.Context, s interface{}, groups ...string) error {
+	if len(groups) == 0 {
+		groups = []string{DefaultGroup}
+	}
+
+	return v.structCtx(&walk{ctx: ctx, groups: groups, visited: visits{}}, s)
+}
+
+// structCtx validates the struct `s` with the walk `w`.
+func (v *Validator) structCtx(w *walk, s interface{}) error {
 	if s == nil {
 		return nil
 	}
@@ -25,64 +77,156 @@ func (v *Validator) Struct(s interface{}) error {
 		return ErrInvalidParamType
 	}
 
-	var errs Errors
-	for i := 0; i < root.NumField(); i++ {
-		value := root.Field(i)
-		structField := root.Type().Field(i)
+	errs, err := v.validateNested(w, nil, reflect.ValueOf(s))
+	if err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// visit identifies a pointer being validated.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// visits holds the pointers being validated, to break reference cycles.
+type visits map[visit]bool
 
-		// filter out private struct fields
-		if structField.PkgPath != "" {
-			continue // Private field
+// walk holds the state of a struct validation.
+type walk struct {
+	ctx context.Context
+	// groups are the validation groups to run the rules of.
+	groups []string
+	// filter selects the fields to validate, nil for all.
+	filter  *pathFilter
+	visited visits
+	// structs holds the structs enclosing the value being validated, from the root.
+	structs []reflect.Value
+}
+
+// validateNested validates the structs held by `val`, located at `path`,
+// following pointers, slices, arrays and map values.
+func (v *Validator) validateNested(w *walk, path Path, val reflect.Value) (Errors, error) {
+	switch val.Kind() {
+	case reflect.Interface:
+		if val.IsNil() {
+			return nil, nil
 		}
 
-		rule := structField.Tag.Get(v.tagname)
-		name := structFieldName(structField)
+		return v.validateNested(w, path, val.Elem())
+	case reflect.Ptr:
+		if val.IsNil() {
+			return nil, nil
+		}
 
-		// check if rule is missing unintentionally.
-		if rule == "" && v.validationRuleRequired {
-			return ErrMissingValidationRule
+		// skip pointers already being validated.
+		key := visit{ptr: val.Pointer(), typ: val.Type()}
+		if w.visited[key] {
+			return nil, nil
 		}
+		w.visited[key] = true
+		defer delete(w.visited, key)
 
-		// validate inner struct.
-		if value.Kind() == reflect.Struct && rule != "-" {
-			if err := v.Struct(value.Interface()); err != nil {
-				switch v := err.(type) {
-				case Errors:
-					errs = append(errs, v...)
-				case Error:
-					errs = append(errs, v)
-				default:
-					return err
-				}
+		return v.validateNested(w, path, val.Elem())
+	case reflect.Struct:
+		return v.validateStruct(w, path, val)
+	case reflect.Slice, reflect.Array:
+		var errs Errors
+		for i := 0; i < val.Len(); i++ {
+			inner, err := v.validateNested(w, path.Index(i), val.Index(i))
+			if err != nil {
+				return nil, err
 			}
+			errs = append(errs, inner...)
 		}
 
-		// skip.
-		if rule == "" || rule == "-" {
-			continue
+		return errs, nil
+	case reflect.Map:
+		var errs Errors
+		for _, key := range sortedMapKeys(val) {
+			inner, err := v.validateNested(w, path.Key(key.Interface()), val.MapIndex(key))
+			if err != nil {
+				return nil, err
+			}
+			errs = append(errs, inner...)
 		}
 
-		// parse rule into AST
-		expr, err := lang.Parse(rule)
-		if err != nil {
-			return err
+		return errs, nil
+	}
+
+	return nil, nil
+}
+
+// validateStruct validates the fields of the struct `root`, located at `path`.
+func (v *Validator) validateStruct(w *walk, path Path, root reflect.Value) (Errors, error) {
+	if err := w.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	plan := v.plan(root.Type())
+	if plan.err != nil {
+		return nil, plan.err
+	}
+
+	w.structs = append(w.structs, root)
+	defer func() { w.structs = w.structs[:len(w.structs)-1] }()
+
+	var errs Errors
+	for _, field := range plan.fields {
+		value := root.Field(field.index)
+		fieldPath := path.Field(field.name)
+
+		run, descend := w.filter.check(fieldPath)
+
+		// validate inner structs.
+		if field.nested && descend {
+			inner, err := v.validateNested(w, fieldPath, value)
+			if err != nil {
+				return nil, err
+			}
+			errs = append(errs, inner...)
 		}
 
-		if err := v.validate(name, expr, value, root); err != nil {
-			switch v := err.(type) {
-			case Errors:
-				errs = append(errs, v...)
-			case Error:
-				errs = append(errs, v)
-			default:
-				return err
+		// skip fields that are not selected.
+		if !run {
+			continue
+		}
+
+		// run the rules of the selected groups.
+		for _, group := range w.groups {
+			program := field.programs[group]
+			if program == nil {
+				continue
+			}
+
+			var err error
+			sc := &scope{ctx: w.ctx, path: fieldPath, structs: w.structs, rule: program.rule}
+			if errs, err = appendError(errs, program.check(sc, value)); err != nil {
+				return nil, err
 			}
 		}
 	}
 
-	if len(errs) > 0 {
-		return errs
+	// run struct-level validations once the fields are validated, unless the struct is not selected.
+	if run, _ := w.filter.check(path); run {
+		errs = append(errs, v.runHooks(plan.hooks, path, root)...)
 	}
 
-	return nil
+	return errs, nil
+}
+
+// sortedMapKeys returns the keys of the map `val`, sorted so errors are reported in a stable order.
+func sortedMapKeys(val reflect.Value) []reflect.Value {
+	keys := val.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+
+	return keys
 }
@@ -0,0 +1,57 @@
+package validate
+
+// EventKind identifies the kind of a trace Event.
+type EventKind int
+
+const (
+	// RuleEntered is traced before an expression is evaluated.
+	RuleEntered EventKind = iota
+	// CallResult is traced after a validation function or an infix comparison returns.
+	CallResult
+	// ShortCircuit is traced when the rest of a rule is skipped: the right-hand side of AND/OR,
+	// every rule of an `omitempty` rule with an empty value, or a `when` rule whose condition does not hold.
+	ShortCircuit
+)
+
+// String returns the name of the event kind.
+func (k EventKind) String() string {
+	switch k {
+	case RuleEntered:
+		return "RuleEntered"
+	case CallResult:
+		return "CallResult"
+	case ShortCircuit:
+		return "ShortCircuit"
+	}
+
+	return ""
+}
+
+// Event describes a step in the evaluation of a rule.
+type Event struct {
+	Kind EventKind
+	// Path of the value being validated.
+	Path Path
+	// Rule is the expression being evaluated.
+	Rule string
+	// Value is the value being validated.
+	Value interface{}
+	// Args holds the resolved arguments of a validation function, for CallResult events.
+	Args []interface{}
+	// Err is the error returned by a validation function, for CallResult events.
+	Err error
+}
+
+// Tracer receives events during the evaluation of rules.
+// Tracers are called synchronously and must be safe for concurrent use.
+type Tracer interface {
+	Trace(Event)
+}
+
+// TracerFunc is an adapter to use ordinary functions as tracers.
+type TracerFunc func(Event)
+
+// Trace implements Tracer.
+func (f TracerFunc) Trace(e Event) {
+	f(e)
+}
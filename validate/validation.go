@@ -1,5 +1,11 @@
 package validate
 
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
 // Validations is a set of `Validation` by tag.
 type Validations map[string]Validation
 
@@ -23,5 +29,120 @@ type Validation interface {
 	Validate(i interface{}, args ...interface{}) error
 }
 
+// ContextValidation is a Validation that receives the context of StructCtx and ValueCtx,
+// i.e. to query a database with a deadline. Validate is used when no context is given.
+type ContextValidation interface {
+	Validation
+	ValidateContext(ctx context.Context, i interface{}, args ...interface{}) error
+}
+
+// ContextValidationFunc is an adapter to use ordinary functions as context validations.
+type ContextValidationFunc func(ctx context.Context, i interface{}, args ...interface{}) error
+
+// Validate implements Validation, with a background context.
+func (f ContextValidationFunc) Validate(i interface{}, args ...interface{}) error {
+	return f(context.Background(), i, args...)
+}
+
+// ValidateContext implements ContextValidation.
+func (f ContextValidationFunc) ValidateContext(ctx context.Context, i interface{}, args ...interface{}) error {
+	return f(ctx, i, args...)
+}
+
 type ValidationFunc = InterfaceValidationWithInterfaceArgsFunc
-type SimpleValidationFunc = SimpleInterfaceValidationFunc
\ No newline at end of file
+type SimpleValidationFunc = SimpleInterfaceValidationFunc
+
+// Registry is a layered set of validations.
+// Lookups fall back to the parent registry when a name is not registered in this layer.
+// Registrations copy the layer, so lookups are safe for concurrent use without locking.
+type Registry struct {
+	parent *Registry
+	// mu serializes registrations.
+	mu sync.Mutex
+	// validations holds the Validations of this layer. The map is never modified once stored.
+	validations atomic.Value
+	// version is incremented on every registration.
+	version uint64
+}
+
+var (
+	// builtinRegistry is the bottom layer of every registry.
+	builtinRegistry = newRegistry(nil, builtins)
+
+	// Shared is the application-wide registry, layered on top of the builtin validations.
+	// Validators created with New use it unless configured WithRegistry.
+	Shared = NewRegistry(builtinRegistry)
+)
+
+// NewRegistry returns an empty registry layered on top of `parent`.
+// A nil parent makes a registry layered on top of the builtin validations.
+func NewRegistry(parent *Registry) *Registry {
+	if parent == nil {
+		parent = builtinRegistry
+	}
+
+	return newRegistry(parent, nil)
+}
+
+func newRegistry(parent *Registry, validations Validations) *Registry {
+	if validations == nil {
+		validations = Validations{}
+	}
+
+	r := &Registry{parent: parent}
+	r.validations.Store(validations)
+	return r
+}
+
+// Register adds a Validation by name to the application-wide registry.
+// Keywords of the rule language, such as `each` or `when`, cannot be called as validations.
+func Register(name string, f Validation) {
+	Shared.Register(name, f)
+}
+
+// Register adds a Validation by name to this layer of the registry.
+func (r *Registry) Register(name string, f Validation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.validations.Load().(Validations)
+	next := make(Validations, len(current)+1)
+	for k, v := range current {
+		next[k] = v
+	}
+	next[name] = f
+
+	r.validations.Store(next)
+	atomic.AddUint64(&r.version, 1)
+}
+
+// Lookup finds a validation by name in this registry or its parents.
+func (r *Registry) Lookup(name string) (Validation, bool) {
+	for ; r != nil; r = r.parent {
+		if f, ok := r.validations.Load().(Validations)[name]; ok {
+			return f, true
+		}
+	}
+
+	return nil, false
+}
+
+// Get looks up a validation by name in this registry or its parents.
+func (r *Registry) Get(name string) (Validation, error) {
+	f, ok := r.Lookup(name)
+	if !ok {
+		return nil, ErrValidationNotFound
+	}
+
+	return f, nil
+}
+
+// generation changes whenever a validation is registered in this registry or its parents.
+func (r *Registry) generation() uint64 {
+	var g uint64
+	for ; r != nil; r = r.parent {
+		g += atomic.LoadUint64(&r.version)
+	}
+
+	return g
+}
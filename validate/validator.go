@@ -3,28 +3,83 @@ package validate
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/olivoil/pkg/validate/internal/lang"
-	"github.com/pkg/errors"
 )
 
 // Validator allows customization of the validation behavior.
 type Validator struct {
-	validations            Validations
+	// validations holds the validator's custom validations, layered on top of registry.
+	validations *Registry
+	// registry is the layer of shared validations, Shared by default.
+	registry *Registry
+	// overrides collects custom validations while options are applied.
+	overrides              Validations
 	validationRuleRequired bool
+	collectEachErrors      bool
+	eachErrorsLimit        int
+	tracer                 Tracer
 	tagname                string
 	err                    error
+	// caches holds the compiled rules, as *caches.
+	caches atomic.Value
+}
+
+// caches holds compiled rules for a given generation of the validator's registry.
+type caches struct {
+	generation uint64
+	// cachedPrograms counts the rules stored in programs. It is 64-bit aligned for atomic operations.
+	cachedPrograms int64
+	// plans caches the compiled rules of each struct type, by reflect.Type.
+	plans sync.Map
+	// programs caches compiled rules, by rule. Rules of Value and Map may come from callers,
+	// so at most maxCachedPrograms are cached.
+	programs sync.Map
+}
+
+// maxCachedPrograms bounds the number of rules cached for Value and Map.
+const maxCachedPrograms = 4096
+
+// cache returns the compiled rules, discarding them if validations were registered since they were compiled.
+func (v *Validator) cache() *caches {
+	generation := v.validations.generation()
+
+	c, _ := v.caches.Load().(*caches)
+	if c == nil || c.generation != generation {
+		c = &caches{generation: generation}
+		v.caches.Store(c)
+	}
+
+	return c
 }
 
 func (v *Validator) registerValidation(name string, f Validation) {
-	v.validations.Set(name, f)
+	v.overrides.Set(name, f)
+}
+
+func (v *Validator) setRegistry(r *Registry) {
+	if r != nil {
+		v.registry = r
+	}
 }
 
 func (v *Validator) setValidationRuleRequired(required bool) {
 	v.validationRuleRequired = required
 }
 
+func (v *Validator) setCollectEachErrors(limit int) {
+	v.collectEachErrors = true
+	v.eachErrorsLimit = limit
+}
+
+func (v *Validator) setTracer(t Tracer) {
+	v.tracer = t
+}
+
 func (v *Validator) setTagname(name string) {
 	if name != "" {
 		v.tagname = name
@@ -43,12 +98,36 @@ func WithValidationRuleRequired(required bool) Option {
 }
 
 // WithCustomValidation adds a custom validation with tag `name`.
+// The validation is only available to this validator.
 func WithCustomValidation(name string, f Validation) Option {
 	return func(v *Validator) {
 		v.registerValidation(name, f)
 	}
 }
 
+// WithRegistry looks up validations in `r` instead of the Shared registry.
+// Custom validations added to the validator take precedence over the ones in `r`.
+func WithRegistry(r *Registry) Option {
+	return func(v *Validator) {
+		v.setRegistry(r)
+	}
+}
+
+// WithEachErrors causes `each()` to report an error for every failing item instead of stopping at the first one.
+// At most `limit` errors are reported by each `each()`, or all of them when `limit` is 0.
+func WithEachErrors(limit int) Option {
+	return func(v *Validator) {
+		v.setCollectEachErrors(limit)
+	}
+}
+
+// WithTracer sends events to `t` while rules are evaluated, to help debug complex rules.
+func WithTracer(t Tracer) Option {
+	return func(v *Validator) {
+		v.setTracer(t)
+	}
+}
+
 // WithTagname changes the tag name used to set each struct field validation.
 // By default, the tagname is `validate`
 func WithTagname(name string) Option {
@@ -57,115 +136,143 @@ func WithTagname(name string) Option {
 	}
 }
 
+// defaultValidator is used by the package-level helpers, so they share a single plan cache.
+var defaultValidator = New()
+
 // New returns a validator with the specified options.
 func New(options ...Option) *Validator {
 	validator := &Validator{
-		validations: builtins,
-		tagname:     "validate",
+		registry:  Shared,
+		overrides: Validations{},
+		tagname:   "validate",
 	}
 
 	for _, o := range options {
 		o(validator)
 	}
 
+	validator.validations = newRegistry(validator.registry, validator.overrides)
+
 	return validator
 }
 
-// validate a value against a expression, optionally within a bounded context `s`.
-// @param expr parsed AST expression for the validation rule to validate
-// @param val value to validate
-// @param s bounded context (typically the struct being validated)
-func (v *Validator) validate(name string, expr lang.Expr, val, s reflect.Value) error {
-	switch exp := expr.(type) {
-	case *lang.BinaryExpr:
-		fmt.Printf("checking %s\n", exp.String())
-		fmt.Printf("LHS: %s\n", exp.LHS.String())
-		fmt.Printf("Op: %s\n", exp.Op.String())
-		fmt.Printf("RHS: %s\n\n", exp.RHS.String())
-
-		err := v.validate(name, exp.LHS, val, s)
-		if err != nil && exp.Op == lang.AND {
-			return err
+// getValueFromPath resolves a value from `v` following the path of a bound param (i.e. `Account.Users[0].Name`).
+// Struct fields are matched by name, then by json name.
+func getValueFromPath(param *lang.BoundParam, segments []lang.Segment, v reflect.Value) (interface{}, error) {
+	for _, segment := range segments {
+		fail := func(format string, args ...interface{}) *PathError {
+			return &PathError{Param: param.String(), Segment: segment.String(), Reason: fmt.Sprintf(format, args...)}
 		}
-		if err == nil && exp.Op == lang.OR {
-			return nil
+		missing := func(format string, args ...interface{}) error {
+			err := fail(format, args...)
+			err.data = true
+			return err
 		}
 
-		return v.validate(name, exp.RHS, val, s)
-	case *lang.ParenExpr:
-		return v.validate(name, exp.Expr, val, s)
-	case *lang.NegativeExpr:
-		if err := v.validate(name, exp.Expr, val, s); err == nil {
-			return Error{Field: name, Validation: exp.String()}
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return nil, missing("nil %s", v.Type())
+			}
+			v = v.Elem()
 		}
 
-		return nil
-	case *lang.EachExpr:
-		switch reflect.TypeOf(val.Interface()).Kind() {
-		case reflect.Slice, reflect.Array, reflect.String:
-			for i := 0; i < val.Len(); i++ {
-				if err := v.validate(name, exp.Expr, val.Index(i), s); err != nil {
-					return err
+		var key interface{}
+		switch segment.Kind {
+		case lang.FieldSegment:
+			if v.Kind() == reflect.Struct {
+				f, ok := fieldByName(v, segment.Name)
+				if !ok {
+					return nil, fail("no field %s in %s", segment.Name, v.Type())
+				}
+				if !f.IsValid() {
+					return nil, missing("nil embedded struct")
 				}
+				v = f
+				continue
 			}
-			return nil
-		default:
-			fmt.Printf("each(%v)", val.Interface())
-			return errors.Wrap(ErrIncompatibleFieldType, "each() requires the value to be an array, slice, or string")
+			key = segment.Name
+		case lang.IndexSegment:
+			switch v.Kind() {
+			case reflect.Slice, reflect.Array, reflect.String:
+				if segment.Index >= v.Len() {
+					return nil, missing("index out of range with length %d", v.Len())
+				}
+				v = v.Index(segment.Index)
+				continue
+			}
+			key = segment.Index
+		case lang.KeySegment:
+			key = segment.Name
+		}
+
+		// look up map keys.
+		if v.Kind() != reflect.Map {
+			return nil, fail("cannot select %s in %s", segment.String(), v.Type())
 		}
-	case *lang.Call:
-		// look up validation function
-		f, ok := v.validations[exp.Name]
+
+		k, ok := mapKey(v.Type().Key(), key)
 		if !ok {
-			return errors.Wrapf(ErrUnknownValidationFunction, "unknown validation: %s", exp.Name)
+			return nil, fail("incompatible key for %s", v.Type())
 		}
 
-		// extract parameters
-		params := []interface{}{}
-		for _, arg := range exp.Args {
-			switch a := arg.(type) {
-			case *lang.BoundParam:
-				p, err := getValueFromStruct(a.Path, s)
-				if err != nil {
-					return err
-				}
-				params = append(params, p)
-			default:
-				if l, ok := arg.(lang.Literal); ok {
-					params = append(params, l.Interface())
+		if v = v.MapIndex(k); !v.IsValid() {
+			return nil, missing("no such key")
+		}
+	}
+
+	if !v.CanInterface() {
+		return nil, &PathError{Param: param.String(), Reason: "unexported field"}
+	}
+
+	return v.Interface(), nil
+}
+
+// fieldByName returns the exported field of struct `v` with the Go or json name `name`.
+// The field is the zero Value when it is promoted from a nil embedded pointer.
+func fieldByName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	if f, ok := t.FieldByName(name); ok && f.PkgPath == "" {
+		for i, index := range f.Index {
+			if i > 0 && v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					return reflect.Value{}, true
 				}
+				v = v.Elem()
 			}
+			v = v.Field(index)
 		}
+		return v, true
+	}
 
-		// call validation
-		if err := f.Validate(val.Interface(), params...); err != nil {
-			return Error{Field: name, Validation: exp.String(), Err: err}
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.PkgPath == "" && jsonName(f.Tag.Get(`json`)) == name {
+			return v.Field(i), true
 		}
-
-		return nil
 	}
 
-	return errors.Wrap(ErrUnknownExpression, expr.String())
+	return reflect.Value{}, false
 }
 
-// getValueFromStruct resolves a value from a struct using a path separated by dots (i.e. `Account.User.Name.First`)
-func getValueFromStruct(keyWithDots string, v reflect.Value) (interface{}, error) {
-	keySlice := strings.Split(keyWithDots, ".")
+// mapKey converts `key` to a map key of type `t`.
+func mapKey(t reflect.Type, key interface{}) (reflect.Value, bool) {
+	k := reflect.ValueOf(key)
 
-	for _, key := range keySlice {
-		if v.Kind() == reflect.Ptr {
-			v = v.Elem()
+	switch t.Kind() {
+	case reflect.Interface:
+		return k, true
+	case reflect.String:
+		if i, ok := key.(int); ok {
+			k = reflect.ValueOf(strconv.Itoa(i))
 		}
-
-		// we only accept structs
-		if v.Kind() != reflect.Struct {
-			return nil, ErrIncompatibleFieldType
+		return k.Convert(t), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if _, ok := key.(int); ok {
+			return k.Convert(t), true
 		}
-
-		v = v.FieldByName(key)
 	}
 
-	return v.Interface(), nil
+	return reflect.Value{}, false
 }
 
 func structFieldName(structField reflect.StructField) string {
@@ -1,10 +1,14 @@
 package validate_test
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/olivoil/pkg/validate"
 	"github.com/pkg/errors"
@@ -121,3 +125,1052 @@ func TestValidator_WithFieldValidationRequired_False(t *testing.T) {
 
 	tests.Test(t, validate.New())
 }
+
+func TestValidator_Struct_ParseError(t *testing.T) {
+	validator := validate.New()
+
+	s := struct {
+		Name string `validate:"required,"`
+	}{
+		Name: "ok",
+	}
+
+	// the error is reported every time, from the cached plan.
+	for i := 0; i < 2; i++ {
+		err := validator.Struct(s)
+		assert.Error(t, err)
+		_, ok := err.(validate.Errors)
+		assert.False(t, ok)
+	}
+}
+
+func TestValidator_Struct_UnknownValidation(t *testing.T) {
+	s := struct {
+		Name string `validate:"nil|unknown"`
+	}{}
+
+	err := validate.New().Struct(s)
+	assert.Equal(t, validate.ErrUnknownValidationFunction, errors.Cause(err))
+}
+
+func TestValidator_Struct_Concurrent(t *testing.T) {
+	validator := validate.New()
+
+	type V struct {
+		Name  string   `validate:"required,len(3)"`
+		Names []string `validate:"each(whitelist('tom','dan'))"`
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				assert.NoError(t, validator.Struct(V{Name: "tom", Names: []string{"dan"}}))
+				assert.Error(t, validator.Struct(V{Name: "tommy"}))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkValidator_Struct(b *testing.B) {
+	validator := validate.New()
+
+	type V struct {
+		Name  string   `validate:"required,len(3)"`
+		Count int      `validate:"nil or whitelist(1,3,5,7)"`
+		Names []string `validate:"each(whitelist('tom','dan'))"`
+	}
+	v := V{Name: "tom", Count: 3, Names: []string{"tom", "dan"}}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := validator.Struct(v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestValidator_WithCustomValidation_Isolated(t *testing.T) {
+	never := validate.SimpleValidationFunc(func(i interface{}) error {
+		return fmt.Errorf("never valid")
+	})
+
+	s := struct {
+		Name string `validate:"never"`
+	}{}
+
+	assert.Error(t, validate.New(validate.WithCustomValidation("never", never)).Struct(s))
+
+	// other validators and the package-level helpers are not affected.
+	assert.Equal(t, validate.ErrUnknownValidationFunction, errors.Cause(validate.New().Struct(s)))
+	assert.Equal(t, validate.ErrUnknownValidationFunction, errors.Cause(validate.Struct(s)))
+}
+
+func TestValidator_WithRegistry(t *testing.T) {
+	always := validate.SimpleValidationFunc(func(i interface{}) error { return nil })
+	never := validate.SimpleValidationFunc(func(i interface{}) error { return fmt.Errorf("never valid") })
+
+	registry := validate.NewRegistry(nil)
+	validator := validate.New(validate.WithRegistry(registry))
+
+	s := struct {
+		Name string `validate:"required,check"`
+	}{
+		Name: "ok",
+	}
+
+	// unknown until registered.
+	assert.Equal(t, validate.ErrUnknownValidationFunction, errors.Cause(validator.Struct(s)))
+
+	// registering invalidates compiled rules.
+	registry.Register("check", never)
+	assert.Error(t, validator.Struct(s))
+	registry.Register("check", always)
+	assert.NoError(t, validator.Struct(s))
+
+	// validator overrides take precedence over the registry.
+	overridden := validate.New(validate.WithRegistry(registry), validate.WithCustomValidation("check", never))
+	assert.Error(t, overridden.Struct(s))
+
+	// builtins are still available.
+	s.Name = ""
+	assert.Error(t, validator.Struct(s))
+}
+
+func TestRegister(t *testing.T) {
+	validate.Register("test_register", validate.SimpleValidationFunc(func(i interface{}) error {
+		return fmt.Errorf("never valid")
+	}))
+
+	assert.Error(t, validate.Value("a", "test_register"))
+	assert.Error(t, validate.New().Value("a", "test_register"))
+}
+
+func TestNew_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("custom%d", i)
+			validator := validate.New(validate.WithCustomValidation(name, validate.SimpleValidationFunc(func(i interface{}) error {
+				return nil
+			})))
+			assert.NoError(t, validator.Value("a", name))
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestValidator_OmitEmpty(t *testing.T) {
+	type V struct {
+		Name  string   `validate:"len(3),omitempty"`
+		Names []string `validate:"omitempty,len(2)"`
+		Count int      `validate:"omitempty,whitelist(1,3,5,7)"`
+	}
+
+	tests := testCases{
+		`valid`: []testCase{
+			{
+				Title:   "skips empty values",
+				V:       V{},
+				IsValid: true,
+			},
+			{
+				Title: "validates values",
+				V: V{
+					Name:  "tom",
+					Names: []string{"tom", "dan"},
+					Count: 3,
+				},
+				IsValid: true,
+			},
+		},
+		`invalid`: []testCase{
+			{
+				Title: "validates non empty values",
+				V: V{
+					Name: "dan!",
+				},
+				IsValid: false,
+			},
+			{
+				Title: "validates non empty slices",
+				V: V{
+					Names: []string{"tom"},
+				},
+				IsValid: false,
+			},
+		},
+	}
+
+	tests.Test(t, validate.New())
+
+	assert.NoError(t, validate.Value(nil, "omitempty"))
+	assert.NoError(t, validate.Value(time.Time{}, "omitempty,rfc3339"))
+	assert.Error(t, validate.Value("", "required|omitempty"))
+	assert.Equal(t, validate.ErrMisplacedModifier, errors.Cause(validate.Value("", "each(omitempty)")))
+}
+
+func TestValidator_Struct_ErrorPaths(t *testing.T) {
+	type Address struct {
+		Zip string `json:"zip" validate:"len(5)"`
+	}
+	type User struct {
+		Address Address `json:"address"`
+	}
+	s := struct {
+		User     User     `json:"user"`
+		Contacts []string `json:"contacts" validate:"each(email)"`
+	}{
+		User:     User{Address: Address{Zip: "123"}},
+		Contacts: []string{"a@example.com", "b@example.com", "invalid"},
+	}
+
+	err := validate.Struct(s)
+	if assert.IsType(t, validate.Errors{}, err) {
+		errs := err.(validate.Errors)
+		assert.Len(t, errs, 2)
+
+		assert.Equal(t, "zip", errs[0].Field)
+		assert.Equal(t, validate.Path{{Name: "user"}, {Name: "address"}, {Name: "zip"}}, errs[0].Path)
+		assert.Equal(t, "user.address.zip", errs[0].Path.String())
+
+		assert.Equal(t, "contacts", errs[1].Field)
+		assert.Equal(t, validate.Path{{Name: "contacts"}, {Index: 2}}, errs[1].Path)
+		assert.Equal(t, "contacts[2]", errs[1].Path.String())
+	}
+}
+
+func TestPath_String(t *testing.T) {
+	assert.Equal(t, "", validate.Path{}.String())
+	assert.Equal(t, "[0][1]", validate.Path{}.Index(0).Index(1).String())
+	assert.Equal(t, "items[3].tags[0]", validate.Path{}.Field("items").Index(3).Field("tags").Index(0).String())
+}
+
+func TestValidator_WithEachErrors(t *testing.T) {
+	s := struct {
+		Contacts []string `json:"contacts" validate:"each(email)"`
+	}{
+		Contacts: []string{"invalid", "a@example.com", "invalid", "invalid"},
+	}
+
+	assert.Equal(t, []string{"contacts[0]"}, errorPaths(validate.New().Struct(s)))
+	assert.Equal(t, []string{"contacts[0]", "contacts[2]", "contacts[3]"}, errorPaths(validate.New(validate.WithEachErrors(0)).Struct(s)))
+	assert.Equal(t, []string{"contacts[0]", "contacts[2]"}, errorPaths(validate.New(validate.WithEachErrors(2)).Struct(s)))
+
+	s.Contacts = []string{"a@example.com"}
+	assert.NoError(t, validate.New(validate.WithEachErrors(0)).Struct(s))
+}
+
+func TestValidator_Struct_Nested(t *testing.T) {
+	type Address struct {
+		Zip string `json:"zip" validate:"len(5)"`
+	}
+	type LineItem struct {
+		Quantity int `json:"quantity" validate:"gt(0)"`
+	}
+	type Option struct {
+		Value string `json:"value" validate:"required"`
+	}
+	type Order struct {
+		Address  *Address          `json:"address"`
+		Items    []LineItem        `json:"items"`
+		Previous [2]*LineItem      `json:"previous"`
+		Options  map[string]Option `json:"options"`
+		Skipped  *Address          `json:"skipped" validate:"-"`
+	}
+
+	assert.NoError(t, validate.Struct(Order{}))
+
+	order := Order{
+		Address:  &Address{Zip: "123"},
+		Items:    []LineItem{{Quantity: 1}, {Quantity: 0}},
+		Previous: [2]*LineItem{nil, {Quantity: -1}},
+		Options:  map[string]Option{"b": {}, "a": {Value: "ok"}, "c": {}},
+		Skipped:  &Address{Zip: "123"},
+	}
+
+	err := validate.Struct(&order)
+	if assert.IsType(t, validate.Errors{}, err) {
+		assert.Equal(t, []string{
+			"address.zip",
+			"items[1].quantity",
+			"previous[1].quantity",
+			"options[b].value",
+			"options[c].value",
+		}, errorPaths(err))
+	}
+}
+
+func TestValidator_Struct_Cycle(t *testing.T) {
+	type Node struct {
+		Name     string  `validate:"required"`
+		Next     *Node   `json:"next"`
+		Children []*Node `json:"children"`
+	}
+
+	root := &Node{}
+	root.Next = root
+	root.Children = []*Node{root, {Name: "child", Next: root}}
+
+	err := validate.Struct(root)
+	if assert.IsType(t, validate.Errors{}, err) {
+		assert.Len(t, err.(validate.Errors), 1)
+	}
+}
+
+func TestValidator_Struct_RecursiveTypes(t *testing.T) {
+	type Tree map[string]Tree
+	type List []List
+	type WithTree struct {
+		T Tree
+		L List
+		N string `validate:"required"`
+	}
+
+	err := validate.Struct(WithTree{T: Tree{"a": Tree{}}, L: List{nil}})
+	if assert.IsType(t, validate.Errors{}, err) {
+		assert.Equal(t, "N", err.(validate.Errors)[0].Path.String())
+	}
+}
+
+func TestValidator_KeysValues(t *testing.T) {
+	type V struct {
+		Labels map[string]string `json:"labels" validate:"keys(match(/^[a-z]+$/)),values(required)"`
+		Limits map[string]int    `json:"limits" validate:"values(gt(0))"`
+	}
+
+	tests := testCases{
+		`valid`: []testCase{
+			{
+				Title:   "empty maps",
+				V:       V{},
+				IsValid: true,
+			},
+			{
+				Title: "valid keys and values",
+				V: V{
+					Labels: map[string]string{"env": "prod", "team": "core"},
+					Limits: map[string]int{"cpu": 2},
+				},
+				IsValid: true,
+			},
+		},
+		`invalid`: []testCase{
+			{
+				Title: "invalid key",
+				V: V{
+					Labels: map[string]string{"Env": "prod"},
+				},
+				IsValid: false,
+			},
+			{
+				Title: "invalid value",
+				V: V{
+					Labels: map[string]string{"env": ""},
+				},
+				IsValid: false,
+			},
+			{
+				Title: "invalid number",
+				V: V{
+					Limits: map[string]int{"cpu": 2, "memory": 0},
+				},
+				IsValid: false,
+			},
+		},
+	}
+
+	tests.Test(t, validate.New())
+
+	err := validate.New(validate.WithEachErrors(0)).Struct(V{Limits: map[string]int{"b": 0, "a": 0, "c": 1}})
+	if assert.IsType(t, validate.Errors{}, err) {
+		errs := err.(validate.Errors)
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "limits[a]", errs[0].Path.String())
+		assert.Equal(t, "limits[b]", errs[1].Path.String())
+	}
+
+	assert.Equal(t, validate.ErrIncompatibleFieldType, errors.Cause(validate.Value([]string{"a"}, "keys(required)")))
+	assert.Equal(t, validate.ErrIncompatibleFieldType, errors.Cause(validate.Value("a", "values(required)")))
+}
+
+func TestValidator_WithTracer(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	tracer := validate.TracerFunc(func(e validate.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, fmt.Sprintf("%s %s %s", e.Kind, e.Path, e.Rule))
+	})
+
+	s := struct {
+		Name  string `json:"name" validate:"nil|len(3)"`
+		Count int    `json:"count" validate:"omitempty,gt(1)"`
+	}{
+		Name: "tom",
+	}
+
+	assert.NoError(t, validate.New(validate.WithTracer(tracer)).Struct(s))
+	assert.Equal(t, []string{
+		"RuleEntered name nil() OR len(3)",
+		"RuleEntered name nil()",
+		"CallResult name nil()",
+		"RuleEntered name len(3)",
+		"CallResult name len(3)",
+		"ShortCircuit count omitempty AND gt(1)",
+	}, events)
+}
+
+type ctxKey struct{}
+
+func TestValidator_StructCtx(t *testing.T) {
+	calls := 0
+	exists := validate.ContextValidationFunc(func(ctx context.Context, i interface{}, args ...interface{}) error {
+		calls++
+		ids, _ := ctx.Value(ctxKey{}).([]string)
+		for _, id := range ids {
+			if id == i {
+				return nil
+			}
+		}
+		return fmt.Errorf("%v does not exist", i)
+	})
+	validator := validate.New(validate.WithCustomValidation("exists", exists))
+
+	s := struct {
+		IDs []string `validate:"each(exists)"`
+	}{
+		IDs: []string{"a", "b"},
+	}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, []string{"a", "b"})
+	assert.NoError(t, validator.StructCtx(ctx, s))
+	assert.Error(t, validator.Struct(s))
+	assert.NoError(t, validator.ValueCtx(ctx, "a", "exists"))
+
+	// evaluation stops when the context is done.
+	calls = 0
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Equal(t, context.Canceled, validator.StructCtx(ctx, s))
+	assert.Equal(t, context.Canceled, validator.ValueCtx(ctx, "a", "exists"))
+	assert.Equal(t, 0, calls)
+
+	// negations do not pass when the inner rule could not be evaluated.
+	assert.Equal(t, context.Canceled, validator.ValueCtx(ctx, "c", "!exists"))
+
+	remote := validate.ContextValidationFunc(func(ctx context.Context, i interface{}, args ...interface{}) error {
+		return errors.Wrap(validate.ErrValidationUnavailable, "backend down")
+	})
+	validator = validate.New(validate.WithCustomValidation("remote", remote))
+	err := validator.StructCtx(context.Background(), struct {
+		ID string `validate:"!remote"`
+	}{})
+	assert.Equal(t, validate.ErrValidationUnavailable, errors.Cause(err))
+	assert.Equal(t, validate.ErrValidationUnavailable, errors.Cause(validator.ValueCtx(context.Background(), "a", "remote")))
+
+	assert.IsType(t, &validate.PathError{}, validate.Struct(struct {
+		Count int `validate:"!lt($.Missing)"`
+	}{}))
+}
+
+func TestValidator_Struct_BoundParamRefs(t *testing.T) {
+	type Line struct {
+		Currency string  `validate:"whitelist($$.Order.Currency)"`
+		Amount   float64 `validate:"lte(^.Limit)"`
+	}
+	type Order struct {
+		Currency string
+		Limit    float64
+		Lines    []Line
+	}
+	type Request struct {
+		Order Order
+	}
+
+	req := Request{
+		Order: Order{
+			Currency: "USD",
+			Limit:    10,
+			Lines: []Line{
+				{Currency: "USD", Amount: 5},
+			},
+		},
+	}
+	assert.NoError(t, validate.Struct(req))
+
+	req.Order.Lines = append(req.Order.Lines, Line{Currency: "EUR", Amount: 11})
+	err := validate.Struct(req)
+	if assert.IsType(t, validate.Errors{}, err) {
+		errs := err.(validate.Errors)
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "Order.Lines[1].Currency", errs[0].Path.String())
+		assert.Equal(t, "Order.Lines[1].Amount", errs[1].Path.String())
+	}
+
+	// there is no struct above the root.
+	s := struct {
+		Amount float64 `validate:"lte(^^.Limit)"`
+	}{}
+	assert.IsType(t, &validate.PathError{}, validate.Struct(s))
+}
+
+func TestValidator_Struct_BoundParamPaths(t *testing.T) {
+	type Item struct {
+		Price float64 `json:"price"`
+	}
+	type V struct {
+		Items  []Item            `json:"items"`
+		Labels map[string]string `json:"labels"`
+		Ptr    *Item             `json:"ptr"`
+		Price  float64           `validate:"lte($.Items[0].Price),lte($.items[1].price)"`
+		Env    string            `validate:"whitelist($.Labels['env'])"`
+	}
+
+	v := V{
+		Items:  []Item{{Price: 10}, {Price: 5}},
+		Labels: map[string]string{"env": "prod"},
+		Price:  5,
+		Env:    "prod",
+	}
+	assert.NoError(t, validate.Struct(v))
+
+	v.Price = 6
+	assert.IsType(t, validate.Errors{}, validate.Struct(v))
+
+	// paths that do not resolve for any value are reported as a *PathError.
+	type Broken struct {
+		Items  []Item
+		Labels map[string]string
+		Ptr    *Item
+		Count  int
+		Value  float64 `validate:"-"`
+	}
+	broken := Broken{Items: []Item{{Price: 1}}, Labels: map[string]string{}}
+
+	assert.IsType(t, &validate.PathError{}, validate.Struct(struct {
+		Broken
+		Price float64 `validate:"lte($.Missing)"`
+	}{Broken: broken}))
+	assert.IsType(t, &validate.PathError{}, validate.Struct(struct {
+		Broken
+		Price float64 `validate:"lte($.Broken.Count[0])"`
+	}{Broken: broken}))
+
+	// paths that do not resolve because of the data fail the validation, and other fields are still validated.
+	pathErrors := func(err error) []string {
+		var reasons []string
+		if errs, ok := err.(validate.Errors); ok {
+			for _, e := range errs {
+				if pathErr, ok := e.Err.(*validate.PathError); ok {
+					reasons = append(reasons, pathErr.Reason)
+				} else {
+					reasons = append(reasons, e.Error())
+				}
+			}
+		}
+		return reasons
+	}
+	assert.Equal(t, []string{
+		"index out of range with length 1",
+		"no such key",
+		"nil *validate_test.Item",
+		"Count failed the 'gt(0)' validation: expected 0 to be greater than 0",
+	}, pathErrors(validate.Struct(struct {
+		Broken
+		Price float64 `validate:"lte($.Broken.Items[2].Price)"`
+		Env   string  `validate:"whitelist($.Broken.Labels['team'])"`
+		Max   float64 `validate:"> $.Broken.Ptr.Price"`
+		Count int     `validate:"gt(0)"`
+	}{Broken: broken})))
+
+	// promoted fields of nil embedded pointers do not resolve.
+	type Embedding struct {
+		*Item
+		Amount float64 `validate:"lte($.Price)"`
+	}
+	err := validate.Struct(Embedding{Amount: 1})
+	if assert.IsType(t, validate.Errors{}, err) {
+		assert.Equal(t, "nil embedded struct", err.(validate.Errors)[0].Err.(*validate.PathError).Reason)
+	}
+	assert.NoError(t, validate.Struct(Embedding{Item: &Item{Price: 2}, Amount: 1}))
+}
+
+func TestValidator_Struct_Arithmetic(t *testing.T) {
+	type User struct {
+		Balance float64
+	}
+	type Booking struct {
+		User   User
+		Start  time.Time
+		End    time.Time `validate:"gte($.Start + 1h),lte($.Start + $.Max)"`
+		Max    time.Duration
+		Amount float64 `validate:"lte($.User.Balance * 1.1)"`
+	}
+
+	start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	b := Booking{
+		User:   User{Balance: 100},
+		Start:  start,
+		End:    start.Add(2 * time.Hour),
+		Max:    3 * time.Hour,
+		Amount: 110,
+	}
+	assert.NoError(t, validate.Struct(b))
+
+	b.Amount = 111
+	assert.IsType(t, validate.Errors{}, validate.Struct(b))
+
+	b.Amount, b.End = 100, start.Add(30*time.Minute)
+	assert.IsType(t, validate.Errors{}, validate.Struct(b))
+
+	b.End = start.Add(4 * time.Hour)
+	assert.IsType(t, validate.Errors{}, validate.Struct(b))
+
+	// operands that cannot be combined are reported as invalid params.
+	s := struct {
+		Name  string
+		Count int `validate:"lte($.Name * 2)"`
+	}{Name: "abc"}
+	assert.Equal(t, validate.ErrInvalidParamType, errors.Cause(validate.Struct(s)))
+}
+
+func TestValidator_Struct_When(t *testing.T) {
+	type Payment struct {
+		Method     string
+		Total      float64
+		CardNumber string `validate:"when($.Method == 'card', required, len(16))"`
+		Reference  string `validate:"when($.Method != 'card' and $.Total >= 100 or $.Method == 'wire', required)"`
+		Notes      string `validate:"when(!$.Total, nil)"`
+	}
+
+	tests := testCases{
+		`valid`: []testCase{
+			{Title: "card", V: Payment{Method: "card", Total: 10, CardNumber: "4242424242424242"}, IsValid: true},
+			{Title: "small cash", V: Payment{Method: "cash", Total: 10}, IsValid: true},
+			{Title: "large cash", V: Payment{Method: "cash", Total: 100, Reference: "abc"}, IsValid: true},
+			{Title: "notes", V: Payment{Method: "cash", Total: 10, Notes: "abc"}, IsValid: true},
+		},
+		`invalid`: []testCase{
+			{Title: "missing card number", V: Payment{Method: "card", Total: 10}, IsValid: false},
+			{Title: "short card number", V: Payment{Method: "card", Total: 10, CardNumber: "4242"}, IsValid: false},
+			{Title: "large cash", V: Payment{Method: "cash", Total: 100}, IsValid: false},
+			{Title: "wire", V: Payment{Method: "wire", Total: 10}, IsValid: false},
+			{Title: "notes without total", V: Payment{Method: "cash", Notes: "abc"}, IsValid: false},
+		},
+	}
+
+	tests.Test(t, validate.New())
+
+	// values that cannot be ordered are reported as invalid params.
+	s := struct {
+		Name  string
+		Count int `validate:"when($.Name > 2, required)"`
+	}{Name: "abc"}
+	assert.Equal(t, validate.ErrInvalidParamType, errors.Cause(validate.Struct(s)))
+}
+
+func TestValidator_Struct_Comparisons(t *testing.T) {
+	type Range struct {
+		Min   int
+		Max   int
+		Value int    `validate:">= $.Min, <= $.Max"`
+		Other string `validate:"!= $.Name"`
+		Name  string
+	}
+
+	tests := testCases{
+		`valid`: []testCase{
+			{V: Range{Min: 1, Max: 3, Value: 1, Name: "a", Other: "b"}, IsValid: true},
+			{V: Range{Min: 1, Max: 3, Value: 3, Name: "a"}, IsValid: true},
+		},
+		`invalid`: []testCase{
+			{V: Range{Min: 1, Max: 3, Value: 0, Name: "a", Other: "b"}, IsValid: false},
+			{V: Range{Min: 1, Max: 3, Value: 4, Name: "a", Other: "b"}, IsValid: false},
+			{V: Range{Min: 1, Max: 3, Value: 2, Name: "a", Other: "a"}, IsValid: false},
+		},
+	}
+
+	tests.Test(t, validate.New())
+
+	err := validate.Struct(Range{Min: 1, Max: 3, Value: 4, Name: "a", Other: "b"})
+	if assert.IsType(t, validate.Errors{}, err) {
+		errs := err.(validate.Errors)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "<= $.Max", errs[0].Validation)
+	}
+}
+
+type hookedPeriod struct {
+	Start time.Time `validate:"required"`
+	End   time.Time
+}
+
+func (p hookedPeriod) Validate() error {
+	if p.End.Before(p.Start) {
+		return validate.Error{Field: "End", Validation: "after_start", Err: fmt.Errorf("expected end to be after start")}
+	}
+	return nil
+}
+
+type hookedBooking struct {
+	Name    string
+	Periods []hookedPeriod
+	Guests  int
+}
+
+func (b *hookedBooking) ValidateStruct(v *validate.Validator, path validate.Path) error {
+	if b.Guests > 2*len(b.Periods) {
+		return fmt.Errorf("too many guests for %s", path)
+	}
+	return nil
+}
+
+func TestValidator_Struct_Hooks(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := hookedBooking{
+		Name:    "trip",
+		Periods: []hookedPeriod{{Start: start, End: start.Add(time.Hour)}},
+		Guests:  2,
+	}
+	assert.NoError(t, validate.Struct(b))
+	assert.NoError(t, validate.Struct(&b))
+
+	b.Periods = append(b.Periods, hookedPeriod{Start: start, End: start.Add(-time.Hour)})
+	b.Guests = 5
+	err := validate.Struct(&b)
+	if assert.IsType(t, validate.Errors{}, err) {
+		errs := err.(validate.Errors)
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "Periods[1].End", errs[0].Path.String())
+		assert.Equal(t, "after_start", errs[0].Validation)
+		assert.Equal(t, "", errs[1].Path.String())
+		assert.Equal(t, "ValidateStruct", errs[1].Validation)
+		assert.Equal(t, "hookedBooking failed the 'ValidateStruct' validation: too many guests for ", errs[1].Error())
+	}
+
+	// field validations and hooks are both reported.
+	err = validate.Struct(hookedPeriod{End: time.Time{}.Add(-time.Hour)})
+	if assert.IsType(t, validate.Errors{}, err) {
+		assert.Len(t, err.(validate.Errors), 2)
+	}
+}
+
+func TestValidator_StructGroups(t *testing.T) {
+	type Address struct {
+		City string `validate:"create:required"`
+	}
+	type User struct {
+		ID      int    `validate:"update:required"`
+		Name    string `validate:"omitempty,len(3);create:required,len(3)"`
+		Email   string `validate:"create:required;update:omitempty,email"`
+		Address *Address
+	}
+
+	u := User{Address: &Address{}}
+	assert.NoError(t, validate.Struct(u))
+	assert.NoError(t, validate.StructGroups(u))
+
+	err := validate.StructGroups(u, "create")
+	if assert.IsType(t, validate.Errors{}, err) {
+		errs := err.(validate.Errors)
+		assert.Len(t, errs, 3)
+		assert.Equal(t, "Name", errs[0].Path.String())
+		assert.Equal(t, "Email", errs[1].Path.String())
+		assert.Equal(t, "Address.City", errs[2].Path.String())
+	}
+
+	err = validate.StructGroups(u, "update")
+	if assert.IsType(t, validate.Errors{}, err) {
+		errs := err.(validate.Errors)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "ID", errs[0].Path.String())
+	}
+
+	u = User{ID: 1, Name: "ab", Email: "user@example.com", Address: &Address{City: "Paris"}}
+	assert.NoError(t, validate.StructGroups(u, "update"))
+	err = validate.StructGroups(u, validate.DefaultGroup, "create")
+	if assert.IsType(t, validate.Errors{}, err) {
+		errs := err.(validate.Errors)
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "Name", errs[0].Path.String())
+		assert.Equal(t, "Name", errs[1].Path.String())
+	}
+
+	s := struct {
+		Name string `validate:"create:required;create:len(3)"`
+	}{}
+	assert.Error(t, validate.Struct(s))
+}
+
+func TestValidator_StructPartial(t *testing.T) {
+	type Item struct {
+		SKU   string  `json:"sku" validate:"required"`
+		Price float64 `json:"price" validate:"> 0"`
+	}
+	type Address struct {
+		City string `json:"city" validate:"required"`
+		Zip  string `json:"zip" validate:"len(5)"`
+	}
+	type Order struct {
+		Name    string  `json:"name" validate:"required"`
+		Max     float64 `json:"max" validate:"> 0"`
+		Total   float64 `json:"total" validate:"<= $.Max"`
+		Address Address `json:"address"`
+		Items   []Item  `json:"items" validate:"len(2)"`
+	}
+
+	o := Order{
+		Max:     10,
+		Total:   5,
+		Address: Address{City: "Paris"},
+		Items:   []Item{{SKU: "a", Price: 1}, {Price: -1}},
+	}
+
+	assert.NoError(t, validate.StructPartial(o, "total", "address.city", "items[0]"))
+	assert.Equal(t, []string{"address.zip"}, errorPaths(validate.StructPartial(o, "address")))
+	assert.Equal(t, []string{"items[1].sku", "items[1].price"}, errorPaths(validate.StructPartial(o, "items.sku", "items[1].price")))
+	assert.Equal(t, []string{"name", "items[1].sku", "items[1].price"}, errorPaths(validate.StructPartial(o, "name", "items")))
+
+	assert.Equal(t, []string{"name", "address.zip", "items[1].sku"}, errorPaths(validate.StructExcept(o, "items[1].price")))
+	assert.NoError(t, validate.StructExcept(o, "name", "address", "items"))
+
+	// bound params resolve fields that are not validated.
+	o.Total = 11
+	assert.Equal(t, []string{"total"}, errorPaths(validate.StructPartial(o, "total")))
+
+	assert.Error(t, validate.StructPartial(o, "items["))
+}
+
+func TestValidator_Map(t *testing.T) {
+	var doc map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"name": "Ada",
+		"max": 10,
+		"total": 12,
+		"contacts": ["ada@example.com", "not a contact"],
+		"address": {"country": "US", "city": "Paris"},
+		"items": [{"sku": "a", "price": 1}, {"price": -1}]
+	}`), &doc)
+	assert.NoError(t, err)
+
+	schema := validate.Schema{
+		"name":        "required,len(3)",
+		"total":       "<= $.max",
+		"contacts":    "each(email)",
+		"address.zip": "required_if($.country, 'US')",
+		"items.sku":   "required",
+		"items.price": "> 0",
+		"items[0]":    "required",
+		"items[2]":    "omitempty,required",
+		"email":       "omitempty,email",
+		"phone":       "required_without($$.email)",
+		"meta.owner":  "required_with(^.name)",
+	}
+
+	err = validate.Map(doc, schema)
+	assert.Equal(t, []string{
+		"address.zip", "contacts[1]", "items[1].price", "items[1].sku", "meta.owner", "phone", "total",
+	}, errorPaths(err))
+
+	errs := err.(validate.Errors)
+	assert.Equal(t, "zip", errs[0].Field)
+
+	// fields of a typed map.
+	assert.NoError(t, validate.Map(map[string]string{"name": "Ada"}, validate.Schema{"name": "len(3)", "email": "nil"}))
+	assert.NoError(t, validate.Map(nil, validate.Schema{"name": "nil"}))
+	assert.NoError(t, validate.Map((*map[string]interface{})(nil), validate.Schema{"name": "nil"}))
+
+	// validations other than nil and required do not apply to missing fields.
+	assert.NoError(t, validate.Map(doc, validate.Schema{
+		"missing.x": "len(3)", "missing.y": "each(email)", "missing.z": "> 0, email",
+	}))
+	assert.Error(t, validate.Map(doc, validate.Schema{"missing.x": "!nil"}))
+
+	// nil values outside of documents are of an incompatible type.
+	err = validate.Value(nil, "email")
+	if assert.IsType(t, validate.Errors{}, err) {
+		assert.Equal(t, validate.ErrIncompatibleFieldType, errors.Cause(err.(validate.Errors)[0].Err))
+	}
+
+	// parse errors and documents that are not maps.
+	assert.Error(t, validate.Map(doc, validate.Schema{"name": "len("}))
+	assert.Error(t, validate.Map(doc, validate.Schema{"items[": "required"}))
+	assert.Equal(t, validate.ErrInvalidParamType, validate.Map([]interface{}{}, validate.Schema{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, validate.MapCtx(ctx, doc, schema))
+}
+
+func TestErrors_Localize(t *testing.T) {
+	type Order struct {
+		Name   string   `json:"name" validate:"required"`
+		Code   string   `json:"code" validate:"len(3)"`
+		Total  float64  `json:"total" validate:"> 0"`
+		Status string   `json:"status" validate:"whitelist('new','paid')"`
+		Tags   []string `json:"tags" validate:"each(!nil)"`
+		Note   string   `json:"note" validate:"odd"`
+	}
+
+	v := validate.New(validate.WithCustomValidation("odd", validate.SimpleValidationFunc(func(i interface{}) error {
+		return fmt.Errorf("expected %v to be odd", i)
+	})))
+
+	err := v.Struct(Order{Code: "ab", Status: "old", Tags: []string{""}})
+	errs, ok := err.(validate.Errors)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	assert.Equal(t, []string{
+		"name is required",
+		"code must have a length of 3",
+		"total must be greater than 0",
+		"status must be one of new, paid",
+		"tags is invalid",
+		errs[5].Error(),
+	}, errs.Localize("en-US"))
+
+	validate.RegisterTranslator("fr", validate.MustCatalog(map[string]string{
+		"required": "{{.Field}} est obligatoire",
+		"len":      "{{.Field}} doit contenir {{index .Args 0}} caractères, pas {{len .Value}}",
+		"odd":      "{{.Field}} doit être impair",
+	}))
+
+	assert.Equal(t, []string{
+		"name est obligatoire",
+		"code doit contenir 3 caractères, pas 2",
+		"total must be greater than 0",
+		"status must be one of new, paid",
+		"tags is invalid",
+		"note doit être impair",
+	}, errs.Localize("fr_CA"))
+
+	// errors of struct-level validations are looked up by validation.
+	e := validate.Error{Field: "End", Validation: "after_start", Err: fmt.Errorf("expected end to be after start")}
+	assert.Equal(t, e.Error(), e.Localize("fr"))
+
+	_, err = validate.NewCatalog(map[string]string{"len": "{{.Field"})
+	assert.Error(t, err)
+}
+
+func TestValidator_Struct_Messages(t *testing.T) {
+	type Contact struct {
+		Name  string   `json:"name" validate:"required @'name_required', len(3)"`
+		Code  string   `json:"code" validate:"len(3) @'Please enter a code of {{index .Args 0}} characters'"`
+		Email string   `json:"email" validate:"(email | phone) @'Please enter an email address or a phone number'"`
+		Tags  []string `json:"tags" validate:"each(len(2) @'tag_length') @'invalid_tags'"`
+		Age   int      `json:"age" validate:"> 0"`
+	}
+
+	validate.RegisterTranslator("es", validate.MustCatalog(map[string]string{
+		"name_required": "{{.Field}} es obligatorio",
+	}))
+
+	err := validate.Struct(Contact{Code: "ab", Email: "nope", Tags: []string{"abc"}})
+	errs, ok := err.(validate.Errors)
+	if !assert.True(t, ok) || !assert.Len(t, errs, 5) {
+		return
+	}
+
+	assert.Equal(t, []string{"name_required", "Please enter a code of {{index .Args 0}} characters",
+		"Please enter an email address or a phone number", "tag_length", ""}, []string{
+		errs[0].Message, errs[1].Message, errs[2].Message, errs[3].Message, errs[4].Message,
+	})
+	assert.Equal(t, "name: name_required", errs[0].Error())
+	assert.Contains(t, errs[4].Error(), "failed the '> 0' validation")
+
+	assert.Equal(t, []string{
+		"name es obligatorio",
+		"Please enter a code of 3 characters",
+		"Please enter an email address or a phone number",
+		"tag_length",
+		"age must be greater than 0",
+	}, errs.Localize("es"))
+}
+
+func TestValidator_Struct_ErrorDetails(t *testing.T) {
+	type Withdrawal struct {
+		Max    int      `json:"max"`
+		Amount int      `json:"amount" validate:"required, lte($.Max)"`
+		Fee    int      `json:"fee" validate:"<= $.Max"`
+		Code   string   `json:"code" validate:"len(3)"`
+		Email  string   `json:"email" validate:"email"`
+		Tags   []string `json:"tags" validate:"each(whitelist('a','b'))"`
+	}
+
+	err := validate.Struct(Withdrawal{Max: 10, Amount: 20, Fee: 11, Code: "ab", Email: "nope", Tags: []string{"c"}})
+	errs, ok := err.(validate.Errors)
+	if !assert.True(t, ok) || !assert.Len(t, errs, 5) {
+		return
+	}
+
+	assert.Equal(t, "lte", errs[0].Code)
+	assert.Equal(t, []interface{}{10}, errs[0].Args)
+	assert.Equal(t, 20, errs[0].Value)
+	assert.Equal(t, "required, lte($.Max)", errs[0].Rule)
+	assert.Equal(t, "lte($.Max)", errs[0].Validation)
+	if cmp, ok := errors.Cause(errs[0].Err).(*validate.ComparisonError); assert.True(t, ok) {
+		assert.Equal(t, &validate.ComparisonError{Value: float64(20), Op: "lte", Bound: float64(10)}, cmp)
+	}
+
+	assert.Equal(t, "lte", errs[1].Code)
+	assert.Equal(t, []interface{}{10}, errs[1].Args)
+	assert.Equal(t, 11, errs[1].Value)
+	assert.Equal(t, "<= $.Max", errs[1].Rule)
+
+	assert.Equal(t, "len", errs[2].Code)
+	assert.Equal(t, &validate.LengthError{Value: "ab", Expected: 3, Actual: 2}, errors.Cause(errs[2].Err))
+
+	assert.Equal(t, "email", errs[3].Code)
+	assert.Equal(t, &validate.FormatError{Value: "nope", Format: "email"}, errors.Cause(errs[3].Err))
+
+	assert.Equal(t, "whitelist", errs[4].Code)
+	assert.Equal(t, "tags[0]", errs[4].Path.String())
+	assert.Equal(t, "c", errs[4].Value)
+	assert.Equal(t, []interface{}{"a", "b"}, errs[4].Args)
+	assert.Equal(t, &validate.InclusionError{Value: "c", Values: []interface{}{"a", "b"}}, errors.Cause(errs[4].Err))
+
+	// errors do not share the arguments of compiled rules.
+	for i := 0; i < 2; i++ {
+		err := validate.Value(3, "whitelist(1,2)")
+		if assert.IsType(t, validate.Errors{}, err) {
+			e := err.(validate.Errors)[0]
+			e.Args[0] = 3
+			errors.Cause(e.Err).(*validate.InclusionError).Values[1] = 3
+		}
+	}
+	program, err := validate.Compile("gt(5)")
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		err := program.Check(1)
+		if assert.IsType(t, validate.Errors{}, err) {
+			err.(validate.Errors)[0].Args[0] = 0
+		}
+	}
+
+	// the rule of a validation group is the source of its section.
+	err = validate.StructGroups(struct {
+		Name string `validate:"len(3); create: required, len(2)"`
+	}{}, "create")
+	if assert.IsType(t, validate.Errors{}, err) {
+		assert.Equal(t, "required, len(2)", err.(validate.Errors)[0].Rule)
+	}
+}
+
+// errorPaths returns the paths of the validation errors in `err`.
+func errorPaths(err error) []string {
+	var paths []string
+	if errs, ok := err.(validate.Errors); ok {
+		for _, e := range errs {
+			paths = append(paths, e.Path.String())
+		}
+	}
+	return paths
+}
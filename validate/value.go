@@ -1,18 +1,29 @@
 package validate
 
 import (
-	"reflect"
-
-	"github.com/olivoil/pkg/validate/internal/lang"
+	"context"
+	"sync/atomic"
 )
 
 // Value validates a single value `i` against a rule `r`.
 func Value(i interface{}, r string) error {
-	return New().Value(i, r)
+	return defaultValidator.Value(i, r)
+}
+
+// ValueCtx validates a single value `i` against a rule `r`, with a context.
+func ValueCtx(ctx context.Context, i interface{}, r string) error {
+	return defaultValidator.ValueCtx(ctx, i, r)
 }
 
 // Value validates a single value `i` against a rule `r`.
+// Rules are compiled once and cached on the Validator.
 func (v *Validator) Value(i interface{}, rule string) error {
+	return v.ValueCtx(context.Background(), i, rule)
+}
+
+// ValueCtx validates a single value `i` against a rule `r`.
+// The context is passed to context validations, and validation stops with the context's error when it is done.
+func (v *Validator) ValueCtx(ctx context.Context, i interface{}, rule string) error {
 	// check if rule is missing unintentionally.
 	if rule == "" && v.validationRuleRequired {
 		return ErrMissingValidationRule
@@ -23,27 +34,30 @@ func (v *Validator) Value(i interface{}, rule string) error {
 		return nil
 	}
 
-	// parse rule into AST
-	expr, err := lang.Parse(rule)
+	program, err := v.program(rule)
 	if err != nil {
 		return err
 	}
 
-	var errs Errors
-	if err := v.validate("", expr, reflect.ValueOf(i), reflect.ValueOf(struct{}{})); err != nil {
-		switch v := err.(type) {
-		case Errors:
-			errs = append(errs, v...)
-		case Error:
-			errs = append(errs, v)
-		default:
-			return err
-		}
+	return program.CheckContext(ctx, i)
+}
+
+// program returns the compiled `rule`, compiling and caching it on first use, up to maxCachedPrograms rules.
+func (v *Validator) program(rule string) (*Program, error) {
+	c := v.cache()
+	if p, ok := c.programs.Load(rule); ok {
+		return p.(*Program), nil
+	}
+
+	p, err := v.Compile(rule)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(errs) > 0 {
-		return errs
+	// rules beyond the bound are compiled on every use.
+	if atomic.AddInt64(&c.cachedPrograms, 1) <= maxCachedPrograms {
+		c.programs.Store(rule, p)
 	}
 
-	return nil
+	return p, nil
 }